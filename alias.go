@@ -0,0 +1,88 @@
+package cachecontrolheader
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Parser parses Cache-Control headers with parse options built once as by [NewOptions]
+// and its own extension aliases registered by [Parser.RegisterExtensionAlias].
+// Aliases are scoped to the Parser, so parsers configured for different caches do not affect each other.
+//
+// A Parser is safe for concurrent parsing once the aliases are registered,
+// but registering aliases concurrently with parsing is not.
+type Parser struct {
+	options Options
+}
+
+// NewParser returns a Parser with the parse options opts.
+func NewParser(opts ...parseOption) *Parser {
+	return &Parser{options: NewOptions(opts...)}
+}
+
+// RegisterExtensionAlias makes the parser read the directive named name as the known directive canonical,
+// e.g. a vendor directive equivalent to no-store. The value, if any, is parsed as the value of canonical,
+// and the header is serialized with canonical. Both names are compared case-insensitively.
+// It returns an error if canonical is not a known directive, or if name is not a valid directive name
+// or is itself a known directive. Registering name again replaces its canonical directive.
+func (p *Parser) RegisterExtensionAlias(name, canonical string) error {
+	name, canonical = strings.ToLower(name), strings.ToLower(canonical)
+	if !contains(directiveNames, canonical) {
+		return fmt.Errorf("unknown canonical directive: %s", canonical)
+	}
+	if !isToken(name) {
+		return fmt.Errorf("invalid directive name: %q", name)
+	}
+	if contains(directiveNames, name) {
+		return fmt.Errorf("alias of known directive: %s", name)
+	}
+	if p.options.option.aliases == nil {
+		p.options.option.aliases = make(map[string]string)
+	}
+	p.options.option.aliases[name] = canonical
+	return nil
+}
+
+// Parse parses a Cache-Control header like [ParseStrict] with the options and aliases of the parser.
+func (p *Parser) Parse(header string) (*Header, error) {
+	return parseWithOption(header, p.options.option)
+}
+
+// ParseRequest parses a Cache-Control header of a request like [ParseRequest] with the options and aliases of the parser.
+func (p *Parser) ParseRequest(header string) (*Header, error) {
+	o := p.options.option
+	o.direction = DirectionRequest
+	return parseWithOption(header, o)
+}
+
+// ParseResponse parses a Cache-Control header of a response like [ParseResponse] with the options and aliases of the parser.
+func (p *Parser) ParseResponse(header string) (*Header, error) {
+	o := p.options.option
+	o.direction = DirectionResponse
+	return parseWithOption(header, o)
+}
+
+// ParseContext parses a Cache-Control header like [ParseContext] with the options and aliases of the parser.
+func (p *Parser) ParseContext(ctx context.Context, header string) (*Header, error) {
+	o := p.options.option
+	o.ctx = ctx
+	return parseWithOption(header, o)
+}
+
+// ParseBytes parses a Cache-Control header like [ParseBytes] with the options and aliases of the parser.
+func (p *Parser) ParseBytes(b []byte) (*Header, error) {
+	return parseBytes(b, p.options.option)
+}
+
+// ParseAndValidate parses and validates a Cache-Control header like [ParseAndValidate]
+// with the options and aliases of the parser.
+func (p *Parser) ParseAndValidate(header string) (*Header, error) {
+	return parseAndValidate(header, p.options.option)
+}
+
+// Lint parses a Cache-Control header and reports every problem found in it like [Lint], with the aliases of the parser.
+// The other parse options of the parser are not used, since Lint always parses leniently.
+func (p *Parser) Lint(header string, opts ...lintOption) (*Header, []Problem) {
+	return lint(header, option{aliases: p.options.option.aliases}, opts)
+}
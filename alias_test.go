@@ -0,0 +1,136 @@
+package cachecontrolheader_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestParser_RegisterExtensionAlias(t *testing.T) {
+	t.Parallel()
+	p := cachecontrolheader.NewParser(cachecontrolheader.CaptureUnknownDirectives())
+	if err := p.RegisterExtensionAlias("X-Edge-No-Store", "no-store"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.RegisterExtensionAlias("x-edge-ttl", "max-age"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		header     string
+		want       *cachecontrolheader.Header
+		wantString string
+	}{
+		{
+			header:     "x-edge-no-store",
+			want:       &cachecontrolheader.Header{NoStore: true},
+			wantString: "no-store",
+		},
+		{
+			header:     "X-EDGE-TTL=60, public",
+			want:       &cachecontrolheader.Header{MaxAge: durationPtr(60 * time.Second), Public: true},
+			wantString: "max-age=60, public",
+		},
+		{
+			header:     "x-other=1",
+			want:       &cachecontrolheader.Header{},
+			wantString: "x-other=1",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			got, err := p.Parse(tt.header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got, ignoreUnexported); diff != "" {
+				t.Errorf("Parser.Parse() mismatch (-want +got):\n%s", diff)
+			}
+			if s := got.String(); s != tt.wantString {
+				t.Errorf("Header.String() = %q, want %q", s, tt.wantString)
+			}
+		})
+	}
+
+	t.Run("invalid value", func(t *testing.T) {
+		t.Parallel()
+		if _, err := p.Parse("x-edge-ttl=1h"); err == nil {
+			t.Error("Parser.Parse() error = nil, want an error")
+		}
+	})
+
+	t.Run("scoped to the parser", func(t *testing.T) {
+		t.Parallel()
+		if _, err := cachecontrolheader.NewParser().Parse("x-edge-no-store"); err == nil {
+			t.Error("Parser.Parse() with another parser error = nil, want an error")
+		}
+		if _, err := cachecontrolheader.ParseStrict("x-edge-no-store"); err == nil {
+			t.Error("ParseStrict() error = nil, want an error")
+		}
+	})
+}
+
+func TestParser_entryPoints(t *testing.T) {
+	t.Parallel()
+	p := cachecontrolheader.NewParser()
+	if err := p.RegisterExtensionAlias("x-edge-no-store", "no-store"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name  string
+		parse func(string) (*cachecontrolheader.Header, error)
+	}{
+		{name: "Parse", parse: p.Parse},
+		{name: "ParseRequest", parse: p.ParseRequest},
+		{name: "ParseResponse", parse: p.ParseResponse},
+		{name: "ParseContext", parse: func(s string) (*cachecontrolheader.Header, error) {
+			return p.ParseContext(context.Background(), s)
+		}},
+		{name: "ParseBytes", parse: func(s string) (*cachecontrolheader.Header, error) {
+			return p.ParseBytes([]byte(s))
+		}},
+		{name: "ParseAndValidate", parse: p.ParseAndValidate},
+		{name: "Lint", parse: func(s string) (*cachecontrolheader.Header, error) {
+			h, _ := p.Lint(s)
+			return h, nil
+		}},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.parse("x-edge-no-store")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(&cachecontrolheader.Header{NoStore: true}, got, ignoreUnexported); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParser_RegisterExtensionAlias_error(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name      string
+		alias     string
+		canonical string
+	}{
+		{name: "unknown canonical", alias: "x-a", canonical: "x-b"},
+		{name: "known alias", alias: "max-age", canonical: "s-maxage"},
+		{name: "invalid alias", alias: "x a", canonical: "no-store"},
+		{name: "empty alias", alias: "", canonical: "no-store"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p := cachecontrolheader.NewParser()
+			if err := p.RegisterExtensionAlias(tt.alias, tt.canonical); err == nil {
+				t.Error("Parser.RegisterExtensionAlias() error = nil, want an error")
+			}
+		})
+	}
+}
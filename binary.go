@@ -0,0 +1,226 @@
+package cachecontrolheader
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// binaryVersion is the version of the binary format written by [Header.MarshalBinary].
+const binaryVersion = 1
+
+// MarshalBinary implements [encoding.BinaryMarshaler] with a compact representation of the header,
+// e.g. for binary caches. The format, version 1, consists of:
+//
+//   - the version byte,
+//   - a uvarint bitmask of the boolean directives, bit i standing for the i-th of them in the order of the [Header] fields,
+//   - a uvarint bitmask of the set duration directives likewise, followed by the varint seconds of each set one,
+//   - the field names of no-cache and then private, each as a uvarint count followed by strings, and
+//   - the extensions as a uvarint count followed by a name string, a byte 1 or 0 telling whether
+//     it has a value, and the value string if it has one, for each,
+//
+// where a string is a uvarint length followed by the bytes.
+// Durations are truncated to seconds as in [Header.String], and the directive order is not kept.
+// Later versions will change the version byte; [Header.UnmarshalBinary] rejects versions it does not know.
+func (h *Header) MarshalBinary() ([]byte, error) {
+	b := []byte{binaryVersion}
+
+	var flags uint64
+	i := 0
+	for _, name := range directiveNames {
+		if f := h.flag(name); f != nil {
+			if *f {
+				flags |= 1 << uint(i)
+			}
+			i++
+		}
+	}
+	b = appendUvarint(b, flags)
+
+	var durations uint64
+	var seconds []int64
+	i = 0
+	for _, name := range directiveNames {
+		if d := h.duration(name); d != nil {
+			if *d != nil {
+				durations |= 1 << uint(i)
+				seconds = append(seconds, int64((*d).Seconds()))
+			}
+			i++
+		}
+	}
+	b = appendUvarint(b, durations)
+	for _, s := range seconds {
+		b = appendVarint(b, s)
+	}
+
+	for _, fs := range [][]string{h.NoCacheFields, h.PrivateFields} {
+		b = appendUvarint(b, uint64(len(fs)))
+		for _, f := range fs {
+			b = appendBinaryString(b, f)
+		}
+	}
+
+	b = appendUvarint(b, uint64(len(h.extensions)))
+	for _, e := range h.extensions {
+		b = appendBinaryString(b, e.name)
+		if !e.hasValue {
+			b = append(b, 0)
+			continue
+		}
+		b = appendBinaryString(append(b, 1), e.value)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler], decoding the format written by [Header.MarshalBinary].
+// The header is replaced entirely.
+func (h *Header) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return errors.New("empty binary data")
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("unsupported binary format version: %d", data[0])
+	}
+	r := binaryReader{b: data[1:]}
+	c := Header{}
+
+	flags := r.uvarint()
+	i := 0
+	for _, name := range directiveNames {
+		if f := c.flag(name); f != nil {
+			*f = flags&(1<<uint(i)) != 0
+			i++
+		}
+	}
+
+	durations := r.uvarint()
+	i = 0
+	for _, name := range directiveNames {
+		if d := c.duration(name); d != nil {
+			if durations&(1<<uint(i)) != 0 {
+				v := time.Duration(r.varint()) * time.Second
+				*d = &v
+			}
+			i++
+		}
+	}
+
+	for _, fs := range []*[]string{&c.NoCacheFields, &c.PrivateFields} {
+		for n := r.count(); n > 0; n-- {
+			*fs = append(*fs, r.string())
+		}
+	}
+
+	for n := r.count(); n > 0; n-- {
+		e := extension{name: r.string()}
+		switch r.byte() {
+		case 0:
+		case 1:
+			e.hasValue = true
+			e.value = r.string()
+		default:
+			r.fail()
+		}
+		c.extensions = append(c.extensions, e)
+	}
+
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.b) > 0 {
+		return errors.New("trailing bytes in binary data")
+	}
+	*h = c
+	return nil
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+func appendBinaryString(b []byte, s string) []byte {
+	return append(appendUvarint(b, uint64(len(s))), s...)
+}
+
+// binaryReader reads the values of the binary format, keeping the first error.
+// Once an error occurs, it returns zero values.
+type binaryReader struct {
+	b   []byte
+	err error
+}
+
+var errTruncatedBinary = errors.New("truncated binary data")
+
+func (r *binaryReader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.b) == 0 {
+		r.err = errTruncatedBinary
+		return 0
+	}
+	c := r.b[0]
+	r.b = r.b[1:]
+	return c
+}
+
+// fail records that the data is malformed, unless an error has already occurred.
+func (r *binaryReader) fail() {
+	if r.err == nil {
+		r.err = errors.New("malformed binary data")
+	}
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		r.err = errTruncatedBinary
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *binaryReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.b)
+	if n <= 0 {
+		r.err = errTruncatedBinary
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+// count reads the number of the following items, which can be no more than the remaining bytes.
+func (r *binaryReader) count() int {
+	v := r.uvarint()
+	if v > uint64(len(r.b)) {
+		r.err = errTruncatedBinary
+		return 0
+	}
+	return int(v)
+}
+
+func (r *binaryReader) string() string {
+	n := r.count()
+	if r.err != nil {
+		return ""
+	}
+	s := string(r.b[:n])
+	r.b = r.b[n:]
+	return s
+}
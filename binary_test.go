@@ -0,0 +1,79 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestHeader_MarshalBinary_UnmarshalBinary(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []string{
+		"",
+		"no-store",
+		"max-age=0",
+		"max-age=3600, max-stale=10, min-fresh=5, s-maxage=2147483648",
+		`no-cache, no-cache="Set-Cookie, Authorization", private="X-A", public, immutable`,
+		"no-transform, only-if-cached, must-revalidate, must-understand, proxy-revalidate",
+		`max-age=60, x-ext="a, b", x-flag, x-empty=, Other=1`,
+		"max-age=60, stale-while-revalidate=30, stale-if-error=0",
+	} {
+		tt := tt
+		t.Run(tt, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := h.MarshalBinary()
+			if err != nil {
+				t.Fatalf("Header.MarshalBinary() got error: %v", err)
+			}
+			got := &cachecontrolheader.Header{Public: true}
+			if err := got.UnmarshalBinary(b); err != nil {
+				t.Fatalf("Header.UnmarshalBinary() got error: %v", err)
+			}
+			if diff := cmp.Diff(h, got, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(h.Extensions(), got.Extensions()); diff != "" {
+				t.Errorf("Header.Extensions() mismatch (-want +got):\n%s", diff)
+			}
+			if got, want := got.String(), h.String(); got != want {
+				t.Errorf("Header.String() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestHeader_UnmarshalBinary_error(t *testing.T) {
+	t.Parallel()
+	valid, err := cachecontrolheader.Parse(`max-age=60, private="X-A", x-ext=1`).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "unknown version", data: []byte{2, 0, 0, 0, 0, 0}},
+		{name: "truncated", data: valid[:len(valid)-1]},
+		{name: "trailing bytes", data: append(append([]byte(nil), valid...), 0)},
+		{name: "huge count", data: []byte{1, 0, 0, 0xff, 0xff, 0xff, 0xff, 0x0f}},
+		{name: "invalid extension value marker", data: []byte{1, 0, 0, 0, 0, 1, 1, 'x', 2}},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := &cachecontrolheader.Header{Public: true}
+			if err := h.UnmarshalBinary(tt.data); err == nil {
+				t.Error("Header.UnmarshalBinary() got no error")
+			}
+			if !h.Public {
+				t.Error("header was modified on error")
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package cachecontrolheader
+
+import (
+	"errors"
+	"strings"
+	"unsafe"
+)
+
+// ParseBytes parses a Cache-Control header like [ParseStrict], but takes the header as []byte,
+// e.g. a slice of a raw HTTP message, to avoid converting the whole header to string.
+// b is not retained after ParseBytes returns, so the caller may reuse it.
+// To parse leniently like [Parse], pass [IgnoreInvalidValues] and [IgnoreUnknownDirectives].
+func ParseBytes(b []byte, opts ...parseOption) (*Header, error) {
+	return parseBytes(b, NewOptions(opts...).option)
+}
+
+// parseBytes does the parsing of ParseBytes with the options already applied.
+func parseBytes(b []byte, option option) (*Header, error) {
+	// The header is read through an unsafe string sharing b,
+	// and the strings kept by the result and the error are copied before returning.
+	h, err := parseWithOption(*(*string)(unsafe.Pointer(&b)), option)
+	if h != nil {
+		h.detach()
+	}
+	if err != nil {
+		var pe *ParseError
+		if errors.As(err, &pe) {
+			pe.header = cloneString(pe.header)
+		}
+		var ive *InvalidValueError
+		if errors.As(err, &ive) {
+			ive.Directive, ive.Value = internName(ive.Directive), cloneString(ive.Value)
+		}
+	}
+	return h, err
+}
+
+// detach copies every string kept by the header so that it no longer refers to the parsed input.
+func (h *Header) detach() {
+	h.Raw = cloneString(h.Raw)
+	for i := range h.order {
+		h.order[i] = internName(h.order[i])
+	}
+	for i := range h.NoCacheFields {
+		h.NoCacheFields[i] = cloneString(h.NoCacheFields[i])
+	}
+	for i := range h.PrivateFields {
+		h.PrivateFields[i] = cloneString(h.PrivateFields[i])
+	}
+	for i := range h.extensions {
+		h.extensions[i].name = cloneString(h.extensions[i].name)
+		h.extensions[i].value = cloneString(h.extensions[i].value)
+	}
+}
+
+// internName returns the constant for a known directive name, or a copy of name otherwise.
+func internName(name string) string {
+	for _, n := range directiveNames {
+		if n == name {
+			return n
+		}
+	}
+	return cloneString(name)
+}
+
+func cloneString(s string) string {
+	if s == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	b.WriteString(s)
+	return b.String()
+}
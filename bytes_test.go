@@ -0,0 +1,114 @@
+package cachecontrolheader_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestParseBytes(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			header: `max-age=3600, must-revalidate, private="Set-Cookie"`,
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:         durationPtr(3600 * time.Second),
+				MustRevalidate: true,
+				PrivateFields:  []string{"Set-Cookie"},
+			},
+		},
+		{
+			header:     "",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+		{
+			header:  "max-age=invalid",
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseBytes([]byte(tt.header))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseBytes_doesNotRetainInput(t *testing.T) {
+	t.Parallel()
+	b := []byte(`x-ext=abc, private="Set-Cookie"`)
+	h, err := cachecontrolheader.ParseBytes(b, cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range b {
+		b[i] = '?'
+	}
+	if got, want := h.StringPreserveOrder(), `x-ext=abc, private="Set-Cookie"`; got != want {
+		t.Errorf("Header.StringPreserveOrder() = %q, want %q", got, want)
+	}
+}
+
+func TestParseBytes_PreserveRawOnError_doesNotRetainInput(t *testing.T) {
+	t.Parallel()
+	b := []byte("max-age=1h")
+	h, err := cachecontrolheader.ParseBytes(b, cachecontrolheader.PreserveRawOnError())
+	if err == nil {
+		t.Fatal("ParseBytes() error = nil, want an error")
+	}
+	for i := range b {
+		b[i] = '?'
+	}
+	if got, want := h.Raw, "max-age=1h"; got != want {
+		t.Errorf("Header.Raw = %q, want %q", got, want)
+	}
+}
+
+func TestParseBytes_InvalidValueError_doesNotRetainInput(t *testing.T) {
+	t.Parallel()
+	b := []byte("max-age=1h")
+	_, err := cachecontrolheader.ParseBytes(b)
+	var ive *cachecontrolheader.InvalidValueError
+	if !errors.As(err, &ive) {
+		t.Fatalf("ParseBytes() error = %v, want an InvalidValueError", err)
+	}
+	for i := range b {
+		b[i] = '?'
+	}
+	if ive.Directive != "max-age" || ive.Value != "1h" {
+		t.Errorf("InvalidValueError = %+v, want Directive %q and Value %q", ive, "max-age", "1h")
+	}
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	header := []byte("max-age=3600, must-revalidate, private")
+	b.Run("ParseStrict", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := cachecontrolheader.ParseStrict(string(header)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ParseBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := cachecontrolheader.ParseBytes(header); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -3,26 +3,37 @@
 package cachecontrolheader
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // directives
 const (
-	dMaxAge          = "max-age"
-	dMaxStale        = "max-stale"
-	dMinFresh        = "min-fresh"
-	dNoCache         = "no-cache"
-	dNoStore         = "no-store"
-	dNoTransform     = "no-transform"
-	dOnlyIfCached    = "only-if-cached"
-	dMustRevalidate  = "must-revalidate"
-	dMustUnderstand  = "must-understand"
-	dPrivate         = "private"
-	dProxyRevalidate = "proxy-revalidate"
-	dPublic          = "public"
-	dSMaxAge         = "s-maxage"
+	dMaxAge               = "max-age"
+	dMaxStale             = "max-stale"
+	dMinFresh             = "min-fresh"
+	dNoCache              = "no-cache"
+	dNoStore              = "no-store"
+	dNoTransform          = "no-transform"
+	dOnlyIfCached         = "only-if-cached"
+	dMustRevalidate       = "must-revalidate"
+	dMustUnderstand       = "must-understand"
+	dPrivate              = "private"
+	dProxyRevalidate      = "proxy-revalidate"
+	dPublic               = "public"
+	dSMaxAge              = "s-maxage"
+	dImmutable            = "immutable"              // RFC 8246
+	dStaleWhileRevalidate = "stale-while-revalidate" // RFC 5861
+	dStaleIfError         = "stale-if-error"         // RFC 5861
 )
 
 // Parse parses a Cache-Control header based on RFC 9111 Section 5.2.
@@ -41,6 +52,61 @@ func ParseStrict(header string, opts ...parseOption) (*Header, error) {
 	return parse(header, opts...)
 }
 
+// ParseContext parses a Cache-Control header like [ParseStrict],
+// checking ctx periodically while tokenizing so that parsing a huge header can be abandoned.
+// It returns ctx.Err() if ctx is done before parsing finishes.
+func ParseContext(ctx context.Context, header string, opts ...parseOption) (*Header, error) {
+	return parse(header, appendOption(opts, func(o *option) {
+		o.ctx = ctx
+	})...)
+}
+
+// ParseAndValidate parses a Cache-Control header like [ParseStrict], and then validates it,
+// returning an error when no-store is combined with directives that allow storing the response,
+// i.e. max-age, s-maxage and public. Unlike [RejectConflicts], the error lists all of them.
+func ParseAndValidate(header string, opts ...parseOption) (*Header, error) {
+	return parseAndValidate(header, NewOptions(opts...).option)
+}
+
+// parseAndValidate does the parsing of ParseAndValidate with the options already applied.
+func parseAndValidate(header string, option option) (*Header, error) {
+	h, err := parseWithOption(header, option)
+	if pe, ok := err.(*ParseError); ok {
+		pe.validate = true
+	}
+	if err != nil {
+		return h, err
+	}
+	if ds := h.conflicts(); len(ds) > 0 {
+		err := &ParseError{Err: conflictError(strings.Join(ds, ", ")), header: header, option: option, validate: true}
+		if option.preserveRawOnError {
+			return &Header{Raw: header}, err
+		}
+		return nil, err
+	}
+	return h, nil
+}
+
+// Options is a set of parse options built once with [NewOptions], for [ParseWithOptions].
+// It saves applying the options on every call in hot paths.
+type Options struct {
+	option option
+}
+
+// NewOptions returns the parse options for [ParseWithOptions].
+func NewOptions(opts ...parseOption) Options {
+	o := Options{}
+	for _, opt := range opts {
+		opt(&o.option)
+	}
+	return o
+}
+
+// ParseWithOptions parses a Cache-Control header like [ParseStrict], with options built by [NewOptions].
+func ParseWithOptions(header string, o Options) (*Header, error) {
+	return parseWithOption(header, o.option)
+}
+
 // IgnoreUnknownDirectives allows to ignore unknown directives.
 func IgnoreUnknownDirectives() parseOption {
 	return func(o *option) {
@@ -50,80 +116,799 @@ func IgnoreUnknownDirectives() parseOption {
 
 // IgnoreInvalidValues allows to ignore directives that have invalid values.
 // Invalid values examples: `max-age=invalid`, `max-stale=1s`
+// It also tolerates a leading UTF-8 BOM, non-ASCII whitespace such as NBSP and obs-fold (line folding),
+// which are syntax errors otherwise, and strips a single layer of parentheses or square brackets
+// around delta-seconds, e.g. `max-age=(60)`, and takes the first of whitespace-separated delta-seconds,
+// e.g. `max-age=60 120` from badly joined headers, which are invalid values otherwise.
 func IgnoreInvalidValues() parseOption {
 	return func(o *option) {
 		o.ignoreInvalidValues = true
 	}
 }
 
+// AssumeNormalized skips the normalization of the header, i.e.
+// lowercasing directive names and handling non-ASCII whitespace, for a faster path.
+// Use it only when the header is known to be already lowercase and ASCII-only,
+// e.g. a header generated by [Header.String].
+// The behavior is undefined for input that is not normalized.
+func AssumeNormalized() parseOption {
+	return func(o *option) {
+		o.assumeNormalized = true
+	}
+}
+
+// CaptureUnknownDirectives allows to keep unknown directives as extensions
+// instead of ignoring them or returning an error.
+// Captured extensions are emitted by [Header.String] after the known directives.
+// It takes precedence over [IgnoreUnknownDirectives], and does not affect known directives:
+// an invalid value of a known directive is still an error unless [IgnoreInvalidValues] is given.
+func CaptureUnknownDirectives() parseOption {
+	return func(o *option) {
+		o.captureUnknownDirectives = true
+	}
+}
+
+// RejectConflicts makes parsing fail when no-store is combined with
+// directives that allow storing the response, i.e. max-age, s-maxage and public.
+func RejectConflicts() parseOption {
+	return func(o *option) {
+		o.rejectConflicts = true
+	}
+}
+
+// RequireNonEmpty makes parsing fail when the header has no directives,
+// i.e. it is empty or consists only of whitespace and commas.
+// By default, such a header is parsed into an empty [Header].
+func RequireNonEmpty() parseOption {
+	return func(o *option) {
+		o.requireNonEmpty = true
+	}
+}
+
+// ReportNonCanonicalCase makes parsing fail when a known directive is not written in lowercase,
+// e.g. `Max-Age=60`, for diagnostics. [Lint] reports such directives as warnings instead.
+// Directive names are case-insensitive (RFC 9111 Section 5.2), so by default they are accepted in any case.
+func ReportNonCanonicalCase() parseOption {
+	return func(o *option) {
+		o.reportNonCanonicalCase = true
+	}
+}
+
+// PreserveRawOnError makes parsing return, along with any error, a [Header] with only Raw set to the header as given,
+// instead of nil, e.g. for a passthrough proxy to forward a header it cannot fully understand verbatim.
+// On success, Raw is left empty.
+func PreserveRawOnError() parseOption {
+	return func(o *option) {
+		o.preserveRawOnError = true
+	}
+}
+
+// MaxDirectives makes parsing fail once more than n directives are found in the header,
+// guarding against adversarial input with a huge number of directives.
+// Empty list elements are not counted. By default, or when n <= 0, the number is unlimited.
+func MaxDirectives(n int) parseOption {
+	return func(o *option) {
+		o.maxDirectives = n
+	}
+}
+
+// ClampSMaxAgeToMaxAge makes parsing reduce s-maxage to max-age when both are set and s-maxage exceeds it,
+// as a defensive measure against shared caches keeping a response longer than private ones.
+// Note that this changes the semantics of the header: s-maxage legitimately overrides max-age in shared caches.
+// It is off by default.
+func ClampSMaxAgeToMaxAge() parseOption {
+	return func(o *option) {
+		o.clampSMaxAge = true
+	}
+}
+
+// TolerateMissingEquals makes lenient parsing, i.e. with [IgnoreInvalidValues], read a known duration directive
+// whose name is followed by whitespace and delta-seconds instead of `=`, e.g. `max-age 3600`, as `max-age=3600`.
+// Without IgnoreInvalidValues, such a directive is still an unknown directive. It is off by default.
+func TolerateMissingEquals() parseOption {
+	return func(o *option) {
+		o.tolerateMissingEquals = true
+	}
+}
+
+// ClampNegativeToZero makes lenient parsing, i.e. with [IgnoreInvalidValues], treat a negative value
+// of max-stale or min-fresh like `max-stale=-1` as zero instead of dropping the directive, as some browsers do.
+// Without IgnoreInvalidValues, negative values are still errors. It is off by default.
+func ClampNegativeToZero() parseOption {
+	return func(o *option) {
+		o.clampNegativeToZero = true
+	}
+}
+
+// DuplicateMode tells how a duration directive appearing more than once is combined, set by [DuplicatePolicy].
+type DuplicateMode int
+
+const (
+	// DuplicateLastWins keeps the last value, which is the default.
+	DuplicateLastWins DuplicateMode = iota
+	// DuplicateFirstWins keeps the first value.
+	DuplicateFirstWins
+	// DuplicateMostRestrictive keeps the value restricting caching the most:
+	// the largest for min-fresh, and the smallest for the other duration directives.
+	DuplicateMostRestrictive
+)
+
+// replaces reports whether the value v of the directive named name replaces old, found earlier.
+func (m DuplicateMode) replaces(name string, old, v time.Duration) bool {
+	switch m {
+	case DuplicateFirstWins:
+		return false
+	case DuplicateMostRestrictive:
+		if name == dMinFresh {
+			return v > old
+		}
+		return v < old
+	default:
+		return true
+	}
+}
+
+// DuplicatePolicy sets how a duration directive appearing more than once, e.g. `max-age=60, max-age=30`,
+// is combined. By default, the last value wins.
+func DuplicatePolicy(m DuplicateMode) parseOption {
+	return func(o *option) {
+		o.duplicateMode = m
+	}
+}
+
+// DisallowDuplicates makes parsing fail when a known directive appears more than once,
+// e.g. `max-age=60, max-age=30`, whose values may conflict, or `no-store, no-store`.
+// To fold repeated boolean directives silently, which are harmless, give [AllowDuplicateBooleans] as well.
+// Names are compared case-insensitively, so `max-age=60, Max-Age=120` is a duplicate too.
+// With [IgnoreInvalidValues], duplicates are combined as set by [DuplicatePolicy] instead.
+func DisallowDuplicates() parseOption {
+	return func(o *option) {
+		o.disallowDuplicates = true
+	}
+}
+
+// AllowDuplicateBooleans makes [DisallowDuplicates] accept a boolean directive appearing more than once,
+// e.g. `no-store, no-store`, folding it into one. It does nothing without DisallowDuplicates.
+func AllowDuplicateBooleans() parseOption {
+	return func(o *option) {
+		o.allowDuplicateBooleans = true
+	}
+}
+
 type option struct {
-	ignoreUnknownDirectives bool
-	ignoreInvalidValues     bool
+	ignoreUnknownDirectives  bool
+	ignoreInvalidValues      bool
+	assumeNormalized         bool
+	captureUnknownDirectives bool
+	rejectConflicts          bool
+	requireNonEmpty          bool
+	reportNonCanonicalCase   bool
+	maxDirectives            int
+	clampSMaxAge             bool
+	clampNegativeToZero      bool
+	tolerateMissingEquals    bool
+	duplicateMode            DuplicateMode
+	disallowDuplicates       bool
+	allowDuplicateBooleans   bool
+	preserveRawOnError       bool
+	ignoredDirectives        []string          // names of the directives dropped by the profile
+	aliases                  map[string]string // canonical directive names by alias, registered to a Parser
+
+	problems *[]Problem      // problems of ignored directives are recorded here when non-nil
+	errs     *[]error        // errors are recorded here instead of returned when non-nil, for ParseError.AllErrors
+	ctx      context.Context // checked every ctxCheckInterval directives when non-nil
+
+	direction Direction // kind of the message the header is parsed for
 }
+
+// ctxCheckInterval is the number of directives parsed between checks of the context.
+const ctxCheckInterval = 64
+
+// report records a problem of the directive if the problems are collected.
+func (o *option) report(severity Severity, directive string, err error) {
+	if o.problems == nil {
+		return
+	}
+	*o.problems = append(*o.problems, Problem{Severity: severity, Directive: directive, Message: err.Error()})
+}
+
+// collect records err if the errors are collected, reporting whether parsing goes on.
+func (o *option) collect(err error) bool {
+	if o.errs == nil {
+		return false
+	}
+	*o.errs = append(*o.errs, err)
+	return true
+}
+
 type parseOption func(*option)
 
+// appendOption returns opts followed by opt, without modifying the backing array of opts owned by the caller.
+func appendOption(opts []parseOption, opt parseOption) []parseOption {
+	return append(opts[:len(opts):len(opts)], opt)
+}
+
 // Header represents a Cache-Control header.
+//
+// A nil duration field means the directive is not present, while a pointer
+// to zero means the directive is present with the value 0, e.g. `max-age=0`.
+// [Header.String] emits every non-nil duration, including zero.
 type Header struct {
 	MaxAge          *time.Duration // max-age directive
 	MaxStale        *time.Duration // max-stale directive
 	MinFresh        *time.Duration // min-fresh directive
 	NoCache         bool           // no-cache directive
+	NoCacheFields   []string       // field names of qualified no-cache directive, e.g. no-cache="Set-Cookie"
 	NoStore         bool           // no-store directive
 	NoTransform     bool           // no-transform directive
 	OnlyIfCached    bool           // only-if-cached directive
 	MustRevalidate  bool           // must-revalidate directive
 	MustUnderstand  bool           // must-understand directive
 	Private         bool           // private directive
+	PrivateFields   []string       // field names of qualified private directive, e.g. private="Set-Cookie"
 	ProxyRevalidate bool           // proxy-revalidate directive
 	Public          bool           // public directive
 	SMaxAge         *time.Duration // s-maxage directive
+	Immutable       bool           // immutable directive (RFC 8246)
+
+	StaleWhileRevalidate *time.Duration // stale-while-revalidate directive (RFC 5861)
+	StaleIfError         *time.Duration // stale-if-error directive (RFC 5861)
+	Raw                  string         // header as given, set only by PreserveRawOnError when parsing fails
+
+	extensions []extension // unknown directives captured by CaptureUnknownDirectives, with their names in original case
+	order      []string    // names of the parsed directives in the order they were found
+}
+
+// extension represents a directive unknown to this package, e.g. `community="UCI"`.
+type extension struct {
+	name     string
+	value    string // value as written
+	hasValue bool   // whether the directive has `=`, which distinguishes `x-key=` from `x-key`
+}
+
+// Extension is a directive unknown to this package, captured by [CaptureUnknownDirectives].
+type Extension struct {
+	Name     string // name as written
+	Value    string // value with quoted-string unquoted
+	HasValue bool   // whether the directive has `=`, e.g. true for `x-key=` and false for `x-key`
+}
+
+// Extensions returns the extensions of the header in the order they were captured.
+// It returns nil if there are none.
+func (h *Header) Extensions() []Extension {
+	if len(h.extensions) == 0 {
+		return nil
+	}
+	es := make([]Extension, len(h.extensions))
+	for i, e := range h.extensions {
+		es[i] = Extension{Name: e.name, Value: unquote(e.value), HasValue: e.hasValue}
+	}
+	return es
+}
+
+// format returns a string representation of the extension.
+// The name is lowercased unless preserveCase is true.
+func (e extension) format(preserveCase bool) string {
+	name := e.name
+	if !preserveCase {
+		name = strings.ToLower(name)
+	}
+	if !e.hasValue {
+		return name
+	}
+	if e.value != "" && e.value[0] != '"' && !isToken(e.value) {
+		// A value written without quotes but not a token, e.g. `x-token=a b`, is quoted to keep it intact.
+		return name + "=" + quoteString(e.value)
+	}
+	return name + "=" + e.value
+}
+
+// RemoveExtension removes the extension directive named name.
+// The name is compared case-insensitively.
+// It does nothing if the extension is not present.
+// The extensions are copied rather than filtered in place, so a copy of the header is left intact.
+func (h *Header) RemoveExtension(name string) {
+	var exts []extension
+	for _, e := range h.extensions {
+		if !strings.EqualFold(e.name, name) {
+			exts = append(exts, e)
+		}
+	}
+	if len(exts) == len(h.extensions) {
+		return
+	}
+	h.extensions = exts
+	var order []string
+	for _, n := range h.order {
+		if !strings.EqualFold(n, name) {
+			order = append(order, n)
+		}
+	}
+	h.order = order
+}
+
+// ClearExtensions removes all extension directives.
+func (h *Header) ClearExtensions() {
+	h.extensions = nil
+}
+
+// SetMaxAge sets the max-age directive to d.
+func (h *Header) SetMaxAge(d time.Duration) {
+	h.MaxAge = &d
+}
+
+// ClearMaxAge removes the max-age directive.
+func (h *Header) ClearMaxAge() {
+	h.MaxAge = nil
+}
+
+// SetMaxStale sets the max-stale directive to d.
+func (h *Header) SetMaxStale(d time.Duration) {
+	h.MaxStale = &d
+}
+
+// ClearMaxStale removes the max-stale directive.
+func (h *Header) ClearMaxStale() {
+	h.MaxStale = nil
+}
+
+// SetMinFresh sets the min-fresh directive to d.
+func (h *Header) SetMinFresh(d time.Duration) {
+	h.MinFresh = &d
+}
+
+// ClearMinFresh removes the min-fresh directive.
+func (h *Header) ClearMinFresh() {
+	h.MinFresh = nil
+}
+
+// SetSMaxAge sets the s-maxage directive to d.
+func (h *Header) SetSMaxAge(d time.Duration) {
+	h.SMaxAge = &d
+}
+
+// ClearSMaxAge removes the s-maxage directive.
+func (h *Header) ClearSMaxAge() {
+	h.SMaxAge = nil
+}
+
+// SetStaleWhileRevalidate sets the stale-while-revalidate directive to d.
+func (h *Header) SetStaleWhileRevalidate(d time.Duration) {
+	h.StaleWhileRevalidate = &d
+}
+
+// ClearStaleWhileRevalidate removes the stale-while-revalidate directive.
+func (h *Header) ClearStaleWhileRevalidate() {
+	h.StaleWhileRevalidate = nil
+}
+
+// SetStaleIfError sets the stale-if-error directive to d.
+func (h *Header) SetStaleIfError(d time.Duration) {
+	h.StaleIfError = &d
+}
+
+// ClearStaleIfError removes the stale-if-error directive.
+func (h *Header) ClearStaleIfError() {
+	h.StaleIfError = nil
+}
+
+// SetPublic sets the public directive, clearing private, which contradicts it.
+// A private qualified with field names is kept, since it only restricts those fields.
+func (h *Header) SetPublic() {
+	h.Public = true
+	h.Private = false
+}
+
+// SetPrivate sets the unqualified private directive, clearing public, which contradicts it.
+func (h *Header) SetPrivate() {
+	h.Private = true
+	h.Public = false
+}
+
+// RoundDurations rounds the durations of the header to whole seconds, halfway values away from zero,
+// e.g. 1500ms to 2s. Since delta-seconds are integers, [Header.String] drops fractions of a second,
+// so rounding first makes the in-memory values match the serialized ones.
+// The setters do not round; call it after setting durations that are not whole seconds.
+func (h *Header) RoundDurations() {
+	for _, name := range directiveNames {
+		if d := h.duration(name); d != nil && *d != nil {
+			v := (*d).Round(time.Second)
+			*d = &v
+		}
+	}
+}
+
+// CachingDisabled reports whether the header effectively disables caching.
+// That is the case when no-store is set, or when no-cache is combined with
+// max-age=0 so that a stored response must be revalidated on every use.
+func (h *Header) CachingDisabled() bool {
+	if h.NoStore {
+		return true
+	}
+	return h.NoCache && h.MaxAge != nil && *h.MaxAge == 0
+}
+
+// AlwaysRevalidate reports whether a stored response must be revalidated on every use
+// while it can still be stored, i.e. "cache but always check".
+// That is the case for no-cache, and for max-age=0 combined with must-revalidate.
+// Unlike [Header.CachingDisabled], it does not consider no-store, which forbids storing at all.
+// A no-cache qualified with field names is not enough, since it only restricts those fields.
+func (h *Header) AlwaysRevalidate() bool {
+	if h.NoCache {
+		return true
+	}
+	return h.MustRevalidate && h.MaxAge != nil && *h.MaxAge == 0
+}
+
+// RevalidationTarget reports what a cache must revalidate before every use of a stored response.
+// whole is true when the whole response must be revalidated, as reported by [Header.AlwaysRevalidate].
+// Otherwise, fields lists the header fields named by a qualified no-cache,
+// which must not be sent without revalidation while the rest of the response may be reused.
+// Both are zero when nothing needs to be revalidated while the response is fresh.
+func (h *Header) RevalidationTarget() (whole bool, fields []string) {
+	if h.AlwaysRevalidate() {
+		return true, nil
+	}
+	if len(h.NoCacheFields) == 0 {
+		return false, nil
+	}
+	return false, append([]string(nil), h.NoCacheFields...)
+}
+
+// IsPrivate reports whether private is present, either unqualified or with field names, e.g. private="Set-Cookie".
+// The Private field is true only for the unqualified form, while the qualified form sets PrivateFields instead.
+func (h *Header) IsPrivate() bool {
+	return h.has(dPrivate)
+}
+
+// IsNoCache reports whether no-cache is present, either unqualified or with field names, e.g. no-cache="Set-Cookie".
+// The NoCache field is true only for the unqualified form, while the qualified form sets NoCacheFields instead.
+// Note that the qualified form does not require revalidating the whole response; see [Header.RevalidationTarget].
+func (h *Header) IsNoCache() bool {
+	return h.has(dNoCache)
 }
 
 // String returns a string representation of the Cache-Control header.
+// Known directives are emitted in the order of the [Header] fields,
+// followed by the extensions in the order they were captured.
+// To control the representation, use [Header.StringWith] instead.
 func (h *Header) String() string {
-	var ds []string
-	if h.MaxAge != nil {
-		ds = append(ds, fmt.Sprintf("%s=%d", dMaxAge, int(h.MaxAge.Seconds())))
+	return h.StringWith()
+}
+
+// StringWith returns a string representation of the Cache-Control header like [Header.String],
+// customized by opts.
+func (h *Header) StringWith(opts ...stringOption) string {
+	return strings.Join(h.directives(opts), ", ")
+}
+
+// StringHuman returns a human-readable representation of the Cache-Control header for debugging,
+// like [Header.String] but with durations formatted by [time.Duration.String], e.g. `max-age=1h0m0s`.
+// It is NOT a valid Cache-Control header; use [Header.String] for the wire format.
+func (h *Header) StringHuman() string {
+	return h.StringWith(func(c *stringConfig) {
+		c.humanDurations = true
+	})
+}
+
+// StringFolded returns a string representation of the Cache-Control header like [Header.String],
+// with an obs-fold, i.e. CRLF followed by a space, after a comma wherever the line, including the comma, would exceed maxLen bytes.
+// The length counts only the header value, so subtract the length of "Cache-Control: " to limit the whole field line.
+// A directive longer than maxLen is put on a line of its own, and maxLen <= 0 means no folding.
+//
+// Deprecated: obs-fold is deprecated (RFC 9112 Section 5.2), and recipients may reject it or replace it with a space.
+// It is only for tools that must generate legacy HTTP/1 messages; use [Header.String] otherwise.
+func (h *Header) StringFolded(maxLen int) string {
+	var b strings.Builder
+	line := 0 // length of the current line
+	ds := h.directives(nil)
+	for i, d := range ds {
+		n := len(d)
+		if i < len(ds)-1 {
+			n += len(",") // room for the comma ending the line if it is folded after d
+		}
+		switch {
+		case i == 0:
+		case maxLen > 0 && line+len(", ")+n > maxLen:
+			b.WriteString(",\r\n ")
+			line = len(" ")
+		default:
+			b.WriteString(", ")
+			line += len(", ")
+		}
+		b.WriteString(d)
+		line += len(d)
 	}
-	if h.MaxStale != nil {
-		ds = append(ds, fmt.Sprintf("%s=%d", dMaxStale, int(h.MaxStale.Seconds())))
+	return b.String()
+}
+
+// AppendTo appends the string representation of the Cache-Control header, the same as [Header.String], to b
+// and returns the extended buffer.
+func (h *Header) AppendTo(b []byte) []byte {
+	for i, d := range h.directives(nil) {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = append(b, d...)
 	}
-	if h.MinFresh != nil {
-		ds = append(ds, fmt.Sprintf("%s=%d", dMinFresh, int(h.MinFresh.Seconds())))
+	return b
+}
+
+// WriteString writes the string representation of the Cache-Control header, the same as [Header.String], to w
+// directive by directive, without building the whole string.
+// It returns the number of bytes written and the first error from w.
+func (h *Header) WriteString(w io.Writer) (int, error) {
+	n := 0
+	for i, d := range h.directives(nil) {
+		if i > 0 {
+			m, err := io.WriteString(w, ", ")
+			n += m
+			if err != nil {
+				return n, err
+			}
+		}
+		m, err := io.WriteString(w, d)
+		n += m
+		if err != nil {
+			return n, err
+		}
 	}
-	if h.NoCache {
-		ds = append(ds, dNoCache)
+	return n, nil
+}
+
+// directives returns the string representations of the directives of the header in emission order,
+// customized by opts as in [Header.StringWith].
+func (h *Header) directives(opts []stringOption) []string {
+	c := stringConfig{}
+	for _, opt := range opts {
+		opt(&c)
 	}
-	if h.NoStore {
-		ds = append(ds, dNoStore)
+	var ds []string
+	emitted := make(map[string]bool, len(c.priority))
+	for _, name := range c.priority {
+		name = strings.ToLower(name)
+		if !emitted[name] {
+			ds = h.appendDirective(ds, name, &c)
+			emitted[name] = true
+		}
 	}
-	if h.NoTransform {
-		ds = append(ds, dNoTransform)
+	for _, name := range directiveNames {
+		if !emitted[name] {
+			ds = h.appendDirective(ds, name, &c)
+		}
 	}
-	if h.OnlyIfCached {
-		ds = append(ds, dOnlyIfCached)
+	exts := h.extensions
+	if c.sortExtensions {
+		exts = append([]extension(nil), exts...)
+		sort.SliceStable(exts, func(i, j int) bool {
+			return strings.ToLower(exts[i].name) < strings.ToLower(exts[j].name)
+		})
 	}
-	if h.MustRevalidate {
-		ds = append(ds, dMustRevalidate)
+	for _, e := range exts {
+		if name := strings.ToLower(e.name); !emitted[name] && c.allowed(name) {
+			ds = append(ds, e.format(c.preserveExtensionCase))
+		}
 	}
-	if h.MustUnderstand {
-		ds = append(ds, dMustUnderstand)
+	return ds
+}
+
+// StringFiltered returns a string representation of the Cache-Control header like [Header.String],
+// with only the directives named in allow, e.g. for a gateway passing through only the directives it understands.
+// The names are case-insensitive and may include extensions; set directives not listed are omitted.
+func (h *Header) StringFiltered(allow []string) string {
+	return h.StringWith(func(c *stringConfig) {
+		c.allow = make(map[string]bool, len(allow))
+		for _, name := range allow {
+			c.allow[strings.ToLower(name)] = true
+		}
+	})
+}
+
+// PreserveExtensionCase makes extension names emitted as they were parsed.
+// By default, extension names are lowercased like the known directives.
+// Extension values are always emitted as they were parsed.
+func PreserveExtensionCase() stringOption {
+	return func(c *stringConfig) {
+		c.preserveExtensionCase = true
 	}
-	if h.Private {
-		ds = append(ds, dPrivate)
+}
+
+// PriorityDirectives makes the named directives emitted first in the given order,
+// for legacy caches honoring only the first directive.
+// The other directives follow in the usual order.
+// Names that are not set in the header are skipped.
+func PriorityDirectives(names ...string) stringOption {
+	return func(c *stringConfig) {
+		c.priority = append(c.priority, names...)
 	}
-	if h.ProxyRevalidate {
-		ds = append(ds, dProxyRevalidate)
+}
+
+// OmitRedundantSMaxAge makes s-maxage omitted when it equals max-age, to keep the header compact.
+// Note that this subtly changes the semantics: s-maxage also implies proxy-revalidate
+// and allows a shared cache to store a response to an authenticated request (RFC 9111 Section 5.2.2.10),
+// which max-age alone does not.
+// By default, both are emitted.
+func OmitRedundantSMaxAge() stringOption {
+	return func(c *stringConfig) {
+		c.omitRedundantSMaxAge = true
 	}
-	if h.Public {
-		ds = append(ds, dPublic)
+}
+
+// SortExtensions makes the extensions emitted in the alphabetical order of their lowercased names,
+// e.g. for stable output in golden tests.
+// By default, they are emitted in the order they were captured.
+func SortExtensions() stringOption {
+	return func(c *stringConfig) {
+		c.sortExtensions = true
 	}
-	if h.SMaxAge != nil {
-		ds = append(ds, fmt.Sprintf("%s=%d", dSMaxAge, int(h.SMaxAge.Seconds())))
+}
+
+type stringConfig struct {
+	preserveExtensionCase bool
+	priority              []string
+	omitRedundantSMaxAge  bool
+	sortExtensions        bool
+	direction             Direction       // directives invalid for the direction are omitted
+	allow                 map[string]bool // only the directives named here are emitted when non-nil
+	humanDurations        bool            // durations are emitted like 1h0m0s, which is not valid on the wire
+}
+type stringOption func(*stringConfig)
+
+// allowed reports whether the directive named name, which must be lowercase, may be emitted.
+func (c *stringConfig) allowed(name string) bool {
+	return c.allow == nil || c.allow[name]
+}
+
+// StringPreserveOrder returns a string representation of the Cache-Control header
+// with the directives in the order they were parsed.
+// Directives that were not parsed, e.g. the ones set on a hand-built header,
+// follow in the same order as [Header.String].
+func (h *Header) StringPreserveOrder() string {
+	c := stringConfig{}
+	var ds []string
+	emitted := make(map[string]bool, len(h.order))
+	// The extensions are indexed by name once, so that a huge header is not serialized in quadratic time.
+	exts := make(map[string][]extension, len(h.extensions))
+	for _, e := range h.extensions {
+		name := strings.ToLower(e.name)
+		exts[name] = append(exts[name], e)
+	}
+	for _, name := range h.order {
+		if contains(directiveNames, name) {
+			ds = h.appendDirective(ds, name, &c)
+		} else {
+			for _, e := range exts[name] {
+				ds = append(ds, e.format(c.preserveExtensionCase))
+			}
+		}
+		emitted[name] = true
+	}
+	for _, name := range directiveNames {
+		if !emitted[name] {
+			ds = h.appendDirective(ds, name, &c)
+		}
+	}
+	for _, e := range h.extensions {
+		if !emitted[strings.ToLower(e.name)] {
+			ds = append(ds, e.format(c.preserveExtensionCase))
+		}
 	}
 	return strings.Join(ds, ", ")
 }
 
+// DirectiveOrder returns the lowercased names of the parsed directives in the order they were first found,
+// e.g. to flag no-store appended after max-age by a misbehaving middleware.
+// The order of directives has no meaning in RFC 9111; it is exposed only for such analysis.
+// It returns nil for a header that was not parsed.
+func (h *Header) DirectiveOrder() []string {
+	if len(h.order) == 0 {
+		return nil
+	}
+	return append([]string(nil), h.order...)
+}
+
+// obsoleteDirectives are the names of the directives introduced by Internet Explorer and never standardized.
+// They are accepted as extensions, even without [CaptureUnknownDirectives], and reported by [Lint] as obsolete.
+var obsoleteDirectives = []string{"post-check", "pre-check"}
+
+// directiveNames lists the known directives in the order of the [Header] fields.
+var directiveNames = []string{
+	dMaxAge,
+	dMaxStale,
+	dMinFresh,
+	dNoCache,
+	dNoStore,
+	dNoTransform,
+	dOnlyIfCached,
+	dMustRevalidate,
+	dMustUnderstand,
+	dPrivate,
+	dProxyRevalidate,
+	dPublic,
+	dSMaxAge,
+	dImmutable,
+	dStaleWhileRevalidate,
+	dStaleIfError,
+}
+
+// appendDirective appends the string representation of the directive named name to ds if it is set.
+// For extensions, every extension with the name is appended.
+func (h *Header) appendDirective(ds []string, name string, c *stringConfig) []string {
+	if c.direction.check(name) != nil || !c.allowed(name) {
+		return ds
+	}
+	switch name {
+	case dMaxAge:
+		return c.appendDuration(ds, name, h.MaxAge)
+	case dMaxStale:
+		return c.appendDuration(ds, name, h.MaxStale)
+	case dMinFresh:
+		return c.appendDuration(ds, name, h.MinFresh)
+	case dNoCache:
+		if c.direction == DirectionRequest {
+			return appendBool(ds, name, h.NoCache)
+		}
+		return appendFields(appendBool(ds, name, h.NoCache), name, h.NoCacheFields)
+	case dNoStore:
+		return appendBool(ds, name, h.NoStore)
+	case dNoTransform:
+		return appendBool(ds, name, h.NoTransform)
+	case dOnlyIfCached:
+		return appendBool(ds, name, h.OnlyIfCached)
+	case dMustRevalidate:
+		return appendBool(ds, name, h.MustRevalidate)
+	case dMustUnderstand:
+		return appendBool(ds, name, h.MustUnderstand)
+	case dPrivate:
+		return appendFields(appendBool(ds, name, h.Private), name, h.PrivateFields)
+	case dProxyRevalidate:
+		return appendBool(ds, name, h.ProxyRevalidate)
+	case dPublic:
+		return appendBool(ds, name, h.Public)
+	case dSMaxAge:
+		if c.omitRedundantSMaxAge && h.MaxAge != nil && h.SMaxAge != nil && *h.MaxAge == *h.SMaxAge {
+			return ds
+		}
+		return c.appendDuration(ds, name, h.SMaxAge)
+	case dImmutable:
+		return appendBool(ds, name, h.Immutable)
+	case dStaleWhileRevalidate:
+		return c.appendDuration(ds, name, h.StaleWhileRevalidate)
+	case dStaleIfError:
+		return c.appendDuration(ds, name, h.StaleIfError)
+	}
+	for _, e := range h.extensions {
+		if strings.EqualFold(e.name, name) {
+			ds = append(ds, e.format(c.preserveExtensionCase))
+		}
+	}
+	return ds
+}
+
+func (c *stringConfig) appendDuration(ds []string, name string, d *time.Duration) []string {
+	if d == nil {
+		return ds
+	}
+	if c.humanDurations {
+		return append(ds, name+"="+d.String())
+	}
+	return append(ds, fmt.Sprintf("%s=%d", name, int(d.Seconds())))
+}
+
+func appendFields(ds []string, name string, fs []string) []string {
+	if len(fs) == 0 {
+		return ds
+	}
+	return append(ds, name+"="+quoteString(strings.Join(fs, ", ")))
+}
+
+func appendBool(ds []string, name string, b bool) []string {
+	if !b {
+		return ds
+	}
+	return append(ds, name)
+}
+
 // parse parses a Cache-Control header based on RFC 9111 Section 5.2.
 // By default, it returns an error when unknown directives found.
 // To ignore unknown directives, use [IgnoreUnknownDirectives] option.
@@ -134,66 +919,572 @@ func parse(header string, opts ...parseOption) (*Header, error) {
 	for _, opt := range opts {
 		opt(&option)
 	}
-	header = strings.ToLower(strings.ReplaceAll(header, " ", ""))
+	return parseWithOption(header, option)
+}
+
+// parseWithOption parses a Cache-Control header with the options already applied.
+// The error is a [*ParseError], except for the error of the context.
+func parseWithOption(header string, option option) (*Header, error) {
+	h, err := parseHeader(header, option)
+	if err == nil {
+		return h, nil
+	}
+	if option.ctx == nil || err != option.ctx.Err() {
+		err = &ParseError{Err: err, header: header, option: option}
+	}
+	if option.preserveRawOnError {
+		return &Header{Raw: header}, err
+	}
+	return nil, err
+}
+
+// parseHeader does the parsing of parseWithOption, returning the first error as is.
+func parseHeader(header string, option option) (*Header, error) {
+	if !option.assumeNormalized {
+		var err error
+		if header, err = normalizeWith(normalizeUnicode, header, option); err != nil {
+			return nil, err
+		}
+		if header, err = normalizeWith(unfold, header, option); err != nil {
+			return nil, err
+		}
+	}
 
 	h := Header{}
-	if header == "" {
-		return &h, nil
-	}
-	directives := strings.Split(header, ",")
-	for _, d := range directives {
-		splited := strings.SplitN(d, "=", 2)
-		switch len(splited) {
-		case 1:
-			switch splited[0] {
-			case dNoCache:
-				h.NoCache = true
-			case dNoStore:
-				h.NoStore = true
-			case dOnlyIfCached:
-				h.OnlyIfCached = true
-			case dMustRevalidate:
-				h.MustRevalidate = true
-			case dMustUnderstand:
-				h.MustUnderstand = true
-			case dPrivate:
-				h.Private = true
-			case dProxyRevalidate:
-				h.ProxyRevalidate = true
-			case dPublic:
-				h.Public = true
-			default:
-				if option.ignoreUnknownDirectives {
+	t := tokenizer{s: header}
+	n := 0 // number of directives found
+	var seen map[string]bool
+	if option.disallowDuplicates {
+		seen = make(map[string]bool)
+	}
+	var ordered map[string]struct{} // names in h.order, built by recordOrder for a huge header
+	for ; ; n++ {
+		if option.ctx != nil && n%ctxCheckInterval == 0 {
+			if err := option.ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		tok, ok, err := t.next()
+		if !ok {
+			break
+		}
+		if option.maxDirectives > 0 && n == option.maxDirectives {
+			// Checked only once, so that the error is collected once while the rest of the directives are checked.
+			err := fmt.Errorf("too many directives: more than %d", option.maxDirectives)
+			if !option.collect(err) {
+				return nil, err
+			}
+		}
+		name := tok.name
+		if !option.assumeNormalized {
+			name = strings.ToLower(name)
+		}
+		if err != nil {
+			if option.ignoreInvalidValues {
+				option.report(SeverityError, name, err)
+				continue
+			}
+			if !option.collect(err) {
+				return nil, err
+			}
+			continue
+		}
+		if option.tolerateMissingEquals && option.ignoreInvalidValues && !tok.hasValue {
+			// e.g. `max-age 3600`, which is a single unknown name otherwise
+			if fs := strings.Fields(tok.name); len(fs) == 2 && h.duration(strings.ToLower(fs[0])) != nil && allDeltaSeconds(fs[1:]) {
+				name = strings.ToLower(fs[0])
+				option.report(SeverityWarning, name, fmt.Errorf("directive(%s) has no '=' before its value: %s", name, tok.name))
+				tok.name, tok.value, tok.rawValue, tok.hasValue = fs[0], fs[1], fs[1], true
+			}
+		}
+		if option.reportNonCanonicalCase && name != tok.name && contains(directiveNames, name) {
+			err := fmt.Errorf("non-canonical case of directive: %s", tok.name)
+			if option.problems != nil {
+				option.report(SeverityWarning, name, err)
+			} else if !option.collect(err) {
+				return nil, err
+			}
+		}
+		if canonical, ok := option.aliases[name]; ok {
+			name = canonical
+		}
+		if contains(option.ignoredDirectives, name) {
+			continue
+		}
+		if contains(obsoleteDirectives, name) {
+			// Kept as an extension rather than rejected, since it still appears in real traffic.
+			option.report(SeverityWarning, name, fmt.Errorf("obsolete directive: %s", name))
+			h.extensions = append(h.extensions, extension{name: tok.name, value: tok.rawValue, hasValue: tok.hasValue})
+			h.recordOrder(name, &ordered)
+			continue
+		}
+		if err := option.direction.check(name); err != nil {
+			if option.ignoreUnknownDirectives {
+				option.report(SeverityError, name, err)
+				continue
+			}
+			if !option.collect(err) {
+				return nil, err
+			}
+			continue
+		}
+		if option.disallowDuplicates && (h.flag(name) != nil || h.duration(name) != nil) {
+			// The bare and valued forms of no-cache and private are different directives here.
+			key := name
+			if tok.hasValue {
+				key += "="
+			}
+			if seen[key] && (tok.hasValue || !option.allowDuplicateBooleans) {
+				err := fmt.Errorf("duplicate directive: %s", name)
+				if !option.ignoreInvalidValues {
+					if !option.collect(err) {
+						return nil, err
+					}
 					continue
 				}
-				return nil, fmt.Errorf("unknown directive: %s", splited[0])
+				option.report(SeverityWarning, name, err)
 			}
-		case 2:
-			k := splited[0]
-			v, err := time.ParseDuration(strings.TrimSpace(splited[1]) + "s")
-			if err != nil {
-				if option.ignoreInvalidValues {
+			seen[key] = true
+		}
+		switch tok.hasValue {
+		case false:
+			if b := h.flag(name); b != nil {
+				*b = true
+				break
+			}
+			if option.captureUnknownDirectives {
+				h.extensions = append(h.extensions, extension{name: tok.name})
+				h.recordOrder(name, &ordered)
+				continue
+			}
+			err := fmt.Errorf("unknown directive: %s", name)
+			if option.ignoreUnknownDirectives {
+				option.report(SeverityError, name, err)
+				continue
+			}
+			if !option.collect(err) {
+				return nil, err
+			}
+			continue
+		case true:
+			// A field-name list is defined only for responses; no-cache in a request is a plain boolean.
+			if fs := h.fields(name); fs != nil && option.direction != DirectionRequest {
+				if f := splitFieldNames(tok.value); len(f) > 0 {
+					*fs = append(*fs, f...)
+					break
+				}
+				// An empty field-name list is treated as the unqualified form, which is more restrictive.
+				err := fmt.Errorf("directive(%s) has an empty field-name list: %s=%s", name, name, tok.rawValue)
+				if !option.ignoreInvalidValues {
+					if !option.collect(err) {
+						return nil, err
+					}
+					continue
+				}
+				option.report(SeverityWarning, name, err)
+				*h.flag(name) = true
+				break
+			}
+			if b := h.flag(name); b != nil {
+				err := fmt.Errorf("directive(%s) takes no value: %s=%s", name, name, tok.rawValue)
+				if !option.ignoreInvalidValues {
+					if !option.collect(err) {
+						return nil, err
+					}
 					continue
-				} else {
-					return nil, fmt.Errorf("failed to parse the value of directive(%s=%s): %w", splited[0], splited[1], err)
 				}
+				option.report(SeverityWarning, name, err)
+				*b = true
+				break
 			}
-			switch k {
-			case dMaxAge:
-				h.MaxAge = &v
-			case dMaxStale:
-				h.MaxStale = &v
-			case dMinFresh:
-				h.MinFresh = &v
-			case dSMaxAge:
-				h.SMaxAge = &v
-			default:
+			d := h.duration(name)
+			if d == nil {
+				if option.captureUnknownDirectives {
+					h.extensions = append(h.extensions, extension{name: tok.name, value: tok.rawValue, hasValue: true})
+					h.recordOrder(name, &ordered)
+					continue
+				}
+				err := fmt.Errorf("unknown directive: %s", name)
 				if option.ignoreUnknownDirectives {
+					option.report(SeverityError, name, err)
+					continue
+				}
+				if !option.collect(err) {
+					return nil, err
+				}
+				continue
+			}
+			value := tok.value
+			if inner, ok := unbracket(value); ok && option.ignoreInvalidValues {
+				option.report(SeverityWarning, name, fmt.Errorf("directive(%s) has a bracketed value: %s=%s", name, name, tok.rawValue))
+				value = inner
+			}
+			if option.clampNegativeToZero && option.ignoreInvalidValues && (name == dMaxStale || name == dMinFresh) && isNegativeInteger(value) {
+				option.report(SeverityWarning, name, fmt.Errorf("directive(%s) has a negative value: %s=%s", name, name, tok.rawValue))
+				value = "0"
+			}
+			if fs := strings.Fields(value); len(fs) > 1 && option.ignoreInvalidValues && allDeltaSeconds(fs) {
+				// e.g. `max-age=60 120` from badly joined `max-age=60, max-age=120`
+				option.report(SeverityWarning, name, fmt.Errorf("directive(%s) has multiple values: %s=%s", name, name, tok.rawValue))
+				value = fs[0]
+			}
+			if strings.TrimSpace(value) == "" {
+				value = "" // e.g. `max-age=" "`, reported as empty rather than as an invalid number
+			}
+			v, err := parseDeltaSeconds(value)
+			if err != nil {
+				err = &InvalidValueError{Directive: name, Value: tok.rawValue, Err: err}
+				if option.ignoreInvalidValues {
+					option.report(SeverityError, name, err)
 					continue
 				}
-				return nil, fmt.Errorf("unknown directive: %s", k)
+				if !option.collect(err) {
+					return nil, err
+				}
+				continue
 			}
+			if *d == nil || option.duplicateMode.replaces(name, **d, v) {
+				*d = &v
+			}
+		}
+		h.recordOrder(name, &ordered)
+	}
+	if option.requireNonEmpty && n == 0 {
+		if err := errors.New("empty header"); !option.collect(err) {
+			return nil, err
 		}
 	}
+	if option.rejectConflicts {
+		if ds := h.conflicts(); len(ds) > 0 && !option.collect(conflictError(ds[0])) {
+			return nil, conflictError(ds[0])
+		}
+	}
+	if option.clampSMaxAge && h.MaxAge != nil && h.SMaxAge != nil && *h.SMaxAge > *h.MaxAge {
+		h.SetSMaxAge(*h.MaxAge)
+	}
 	return &h, nil
 }
+
+// normalizeWith normalizes the header with normalize.
+// If the errors are collected, it records the error instead of returning it,
+// and goes on with the header normalized leniently as with IgnoreInvalidValues.
+func normalizeWith(normalize func(string, option) (string, error), header string, o option) (string, error) {
+	normalized, err := normalize(header, o)
+	if err == nil || !o.collect(err) {
+		return normalized, err
+	}
+	o.ignoreInvalidValues, o.problems = true, nil
+	return normalize(header, o)
+}
+
+// unbracket strips a single layer of parentheses or square brackets around s, e.g. `(60)`,
+// written by some broken generators. It returns false if s is not bracketed.
+func unbracket(s string) (string, bool) {
+	if len(s) < 2 {
+		return s, false
+	}
+	switch s[:1] + s[len(s)-1:] {
+	case "()", "[]":
+		return s[1 : len(s)-1], true
+	}
+	return s, false
+}
+
+// allDeltaSeconds reports whether every element of ss consists only of digits.
+func allDeltaSeconds(ss []string) bool {
+	for _, s := range ss {
+		if _, err := parseDeltaSeconds(s); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// isNegativeInteger reports whether s is a minus sign followed by digits.
+func isNegativeInteger(s string) bool {
+	if len(s) < 2 || s[0] != '-' {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// maxDeltaSeconds is the value delta-seconds greater than it are treated as (RFC 9111 Section 1.2.2).
+const maxDeltaSeconds = 1 << 31
+
+// parseDeltaSeconds parses delta-seconds (RFC 9111 Section 1.2.2), a non-negative integer number of seconds.
+// Signs and unit suffixes are rejected, and a value greater than 2^31 is treated as 2^31.
+func parseDeltaSeconds(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, errors.New("empty delta-seconds")
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, fmt.Errorf("invalid delta-seconds %q", s)
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n > maxDeltaSeconds {
+		// Only the range error is possible since s consists of digits.
+		n = maxDeltaSeconds
+	}
+	return time.Duration(n) * time.Second, nil
+}
+
+// unfold replaces each obs-fold, i.e. CRLF followed by spaces or tabs (RFC 9112 Section 5.2),
+// with a single space when invalid values are ignored. Otherwise, it returns a syntax error for it,
+// since obs-fold is deprecated (RFC 9110 Section 5.5).
+func unfold(header string, option option) (string, error) {
+	i := strings.Index(header, "\r\n")
+	if i < 0 {
+		return header, nil
+	}
+	var b strings.Builder
+	for ; i >= 0; i = strings.Index(header, "\r\n") {
+		j := i + 2
+		for j < len(header) && isOWS(header[j]) {
+			j++
+		}
+		if j == i+2 {
+			// CRLF not followed by whitespace is not a fold; keep it for the tokenizer to handle.
+			b.WriteString(header[:j])
+			header = header[j:]
+			continue
+		}
+		err := errors.New("syntax error: obs-fold")
+		if !option.ignoreInvalidValues {
+			return "", err
+		}
+		option.report(SeverityWarning, "", err)
+		b.WriteString(header[:i])
+		b.WriteByte(' ')
+		header = header[j:]
+	}
+	b.WriteString(header)
+	return b.String(), nil
+}
+
+// normalizeUnicode strips a leading UTF-8 BOM and replaces non-ASCII whitespace with spaces
+// when invalid values are ignored. Otherwise, it returns a syntax error for them.
+func normalizeUnicode(header string, option option) (string, error) {
+	if isASCII(header) {
+		return header, nil
+	}
+	if strings.HasPrefix(header, "\uFEFF") {
+		err := errors.New("syntax error: byte order mark")
+		if !option.ignoreInvalidValues {
+			return "", err
+		}
+		option.report(SeverityWarning, "", err)
+		header = strings.TrimPrefix(header, "\uFEFF")
+	}
+	var err error
+	header = strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf || !unicode.IsSpace(r) {
+			return r
+		}
+		if err == nil {
+			err = fmt.Errorf("syntax error: non-ASCII whitespace %U", r)
+		}
+		return ' '
+	}, header)
+	if err != nil {
+		if !option.ignoreInvalidValues {
+			return "", err
+		}
+		option.report(SeverityWarning, "", err)
+	}
+	return header, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// fields returns the field-name list of the directive named name, e.g. no-cache="Set-Cookie".
+// It returns nil if name does not take a field-name list.
+func (h *Header) fields(name string) *[]string {
+	switch name {
+	case dNoCache:
+		return &h.NoCacheFields
+	case dPrivate:
+		return &h.PrivateFields
+	}
+	return nil
+}
+
+// flag returns the field of the boolean directive named name.
+// It returns nil if name is not a boolean directive.
+func (h *Header) flag(name string) *bool {
+	switch name {
+	case dNoCache:
+		return &h.NoCache
+	case dNoStore:
+		return &h.NoStore
+	case dNoTransform:
+		return &h.NoTransform
+	case dOnlyIfCached:
+		return &h.OnlyIfCached
+	case dMustRevalidate:
+		return &h.MustRevalidate
+	case dMustUnderstand:
+		return &h.MustUnderstand
+	case dPrivate:
+		return &h.Private
+	case dProxyRevalidate:
+		return &h.ProxyRevalidate
+	case dPublic:
+		return &h.Public
+	case dImmutable:
+		return &h.Immutable
+	}
+	return nil
+}
+
+// duration returns the field of the duration directive named name.
+// It returns nil if name is not a duration directive.
+func (h *Header) duration(name string) **time.Duration {
+	switch name {
+	case dMaxAge:
+		return &h.MaxAge
+	case dMaxStale:
+		return &h.MaxStale
+	case dMinFresh:
+		return &h.MinFresh
+	case dSMaxAge:
+		return &h.SMaxAge
+	case dStaleWhileRevalidate:
+		return &h.StaleWhileRevalidate
+	case dStaleIfError:
+		return &h.StaleIfError
+	}
+	return nil
+}
+
+// orderScanLimit is the number of recorded names up to which recordOrder scans them
+// rather than looking them up in a map.
+const orderScanLimit = 16
+
+// recordOrder records the directive named name as parsed unless it was already parsed.
+// Once many names are recorded, they are looked up in ordered, which is built then,
+// so that a huge header with distinct directives is not parsed in quadratic time
+// while a typical header allocates no map.
+func (h *Header) recordOrder(name string, ordered *map[string]struct{}) {
+	if *ordered == nil {
+		if len(h.order) < orderScanLimit {
+			for _, n := range h.order {
+				if n == name {
+					return
+				}
+			}
+			h.order = append(h.order, name)
+			return
+		}
+		*ordered = make(map[string]struct{}, 2*len(h.order))
+		for _, n := range h.order {
+			(*ordered)[n] = struct{}{}
+		}
+	}
+	if _, ok := (*ordered)[name]; ok {
+		return
+	}
+	(*ordered)[name] = struct{}{}
+	h.order = append(h.order, name)
+}
+
+// ParseError is the error returned by the parse functions, e.g. [ParseStrict], for the first problem found.
+// Parsing stops there, so the rest of the header is not checked; [ParseError.AllErrors] checks it.
+// The error of the context given to [ParseContext] is returned as is instead.
+type ParseError struct {
+	Err error // first problem found
+
+	header   string
+	option   option
+	validate bool // whether conflicts are errors as in ParseAndValidate
+	once     sync.Once
+	all      []error
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// AllErrors returns the errors of every problem in the header, in the order they are found,
+// e.g. to report them all at once. The header is parsed again with the same options as the failed parsing,
+// going on past each problem; a directive with a problem is skipped, as if it were not in the header.
+// The errors are found on the first call and returned by later calls as well.
+func (e *ParseError) AllErrors() []error {
+	e.once.Do(func() {
+		e.all = allErrors(e.header, e.option, e.validate)
+		if len(e.all) == 0 {
+			e.all = []error{e.Err}
+		}
+	})
+	return append([]error(nil), e.all...)
+}
+
+// allErrors parses the header, collecting the errors of every problem found instead of stopping at the first.
+// If validate is true, the conflicts are collected as well, as in ParseAndValidate.
+func allErrors(header string, option option, validate bool) []error {
+	var errs []error
+	option.ctx, option.problems, option.errs = nil, nil, &errs
+	h, _ := parseHeader(header, option)
+	if ds := h.conflicts(); validate && len(ds) > 0 {
+		errs = append(errs, conflictError(strings.Join(ds, ", ")))
+	}
+	return errs
+}
+
+// InvalidValueError is the error for a directive with a value it does not accept,
+// e.g. `max-age=1h`, or `max-age= ` with a whitespace-only value.
+type InvalidValueError struct {
+	Directive string // name of the directive
+	Value     string // value as written
+	Err       error  // reason the value is invalid
+}
+
+// Error returns the error message.
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("failed to parse the value of directive(%s=%s): %v", e.Directive, e.Value, e.Err)
+}
+
+// Unwrap returns the reason the value is invalid.
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// conflictError returns an error telling the directive contradicts no-store.
+func conflictError(directive string) error {
+	return fmt.Errorf("conflicting directives: %s and %s", dNoStore, directive)
+}
+
+// conflicts returns the directives contradicting no-store.
+func (h *Header) conflicts() []string {
+	if !h.NoStore {
+		return nil
+	}
+	var ds []string
+	if h.MaxAge != nil {
+		ds = append(ds, dMaxAge)
+	}
+	if h.Public {
+		ds = append(ds, dPublic)
+	}
+	if h.SMaxAge != nil {
+		ds = append(ds, dSMaxAge)
+	}
+	return ds
+}
@@ -1,13 +1,21 @@
 package cachecontrolheader_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/mi-wada/cachecontrolheader"
 )
 
+var ignoreUnexported = cmpopts.IgnoreUnexported(cachecontrolheader.Header{})
+
 func durationPtr(d time.Duration) *time.Duration {
 	return &d
 }
@@ -30,6 +38,10 @@ func TestParse(t *testing.T) {
 			header: "",
 			want:   &cachecontrolheader.Header{},
 		},
+		{
+			header: " ",
+			want:   &cachecontrolheader.Header{},
+		},
 		{
 			header: "unknown",
 			want:   &cachecontrolheader.Header{},
@@ -42,12 +54,61 @@ func TestParse(t *testing.T) {
 			header: "max-age=invalid",
 			want:   &cachecontrolheader.Header{},
 		},
+		{
+			header: "no-store=1, public=true",
+			want: &cachecontrolheader.Header{
+				NoStore: true,
+				Public:  true,
+			},
+		},
+		{
+			header: "no-transform",
+			want: &cachecontrolheader.Header{
+				NoTransform: true,
+			},
+		},
+		{
+			header: "Max-Age=60, NO-STORE",
+			want: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(60 * time.Second),
+				NoStore: true,
+			},
+		},
+		{
+			header: "\uFEFFmax-age=60, private",
+			want: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(60 * time.Second),
+				Private: true,
+			},
+		},
+		{
+			header: "max-age=60,\u00a0private",
+			want: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(60 * time.Second),
+				Private: true,
+			},
+		},
+		{
+			header: "max-age=31536000, immutable",
+			want: &cachecontrolheader.Header{
+				MaxAge:    durationPtr(31536000 * time.Second),
+				Immutable: true,
+			},
+		},
+		{
+			header: "max-age=60, stale-while-revalidate=30, stale-if-error=86400",
+			want: &cachecontrolheader.Header{
+				MaxAge:               durationPtr(60 * time.Second),
+				StaleWhileRevalidate: durationPtr(30 * time.Second),
+				StaleIfError:         durationPtr(86400 * time.Second),
+			},
+		},
 	} {
 		tt := tt
 		t.Run(tt.header, func(t *testing.T) {
 			t.Parallel()
 			h := cachecontrolheader.Parse(tt.header)
-			if diff := cmp.Diff(tt.want, h); diff != "" {
+			if diff := cmp.Diff(tt.want, h, ignoreUnexported); diff != "" {
 				t.Errorf("Header mismatch (-want +got):\n%s", diff)
 			}
 		})
@@ -101,6 +162,22 @@ func TestParseStrict(t *testing.T) {
 			header:  "max-age=10s",
 			wantErr: true,
 		},
+		{
+			header:  "no-store=1",
+			wantErr: true,
+		},
+		{
+			header:  "\uFEFFmax-age=60, private",
+			wantErr: true,
+		},
+		{
+			header:  "max-age=60,\u00a0private",
+			wantErr: true,
+		},
+		{
+			header:  "public=true",
+			wantErr: true,
+		},
 	} {
 		tt := tt
 		t.Run(tt.header, func(t *testing.T) {
@@ -109,7 +186,7 @@ func TestParseStrict(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
 			}
-			if diff := cmp.Diff(tt.wantHeader, h); diff != "" {
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
 				t.Errorf("Header mismatch (-want +got):\n%s", diff)
 			}
 		})
@@ -163,7 +240,7 @@ func TestParseStrict_IgnoreUnknownDirectives(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
 			}
-			if diff := cmp.Diff(tt.wantHeader, h); diff != "" {
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
 				t.Errorf("Header mismatch (-want +got):\n%s", diff)
 			}
 		})
@@ -201,6 +278,12 @@ func TestParseStrict_IgnoreInvalidValues(t *testing.T) {
 			header:     "max-age=10s",
 			wantHeader: &cachecontrolheader.Header{},
 		},
+		{
+			header: "no-store=1",
+			wantHeader: &cachecontrolheader.Header{
+				NoStore: true,
+			},
+		},
 		{
 			header:  "unknown",
 			wantErr: true,
@@ -217,7 +300,7 @@ func TestParseStrict_IgnoreInvalidValues(t *testing.T) {
 			if (err != nil) != tt.wantErr {
 				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
 			}
-			if diff := cmp.Diff(tt.wantHeader, h); diff != "" {
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
 				t.Errorf("Header mismatch (-want +got):\n%s", diff)
 			}
 		})
@@ -238,6 +321,29 @@ func TestHeader_String(t *testing.T) {
 			},
 			want: "max-age=3600, must-revalidate, private",
 		},
+		{
+			header: &cachecontrolheader.Header{
+				MaxAge: durationPtr(0),
+			},
+			want: "max-age=0",
+		},
+		{
+			header: &cachecontrolheader.Header{
+				MaxAge:   durationPtr(0),
+				MaxStale: durationPtr(0),
+				MinFresh: durationPtr(0),
+				SMaxAge:  durationPtr(0),
+			},
+			want: "max-age=0, max-stale=0, min-fresh=0, s-maxage=0",
+		},
+		{
+			header: &cachecontrolheader.Header{
+				StaleIfError:         durationPtr(600 * time.Second),
+				StaleWhileRevalidate: durationPtr(30 * time.Second),
+				Immutable:            true,
+			},
+			want: "immutable, stale-while-revalidate=30, stale-if-error=600",
+		},
 		{
 			header: &cachecontrolheader.Header{},
 			want:   "",
@@ -252,3 +358,2115 @@ func TestHeader_String(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStrict_AssumeNormalized(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			header: "max-age=3600,must-revalidate,private",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:         durationPtr(3600 * time.Second),
+				MustRevalidate: true,
+				Private:        true,
+			},
+		},
+		{
+			header:     "",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+		{
+			header:  "max-age=3600,unknown",
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.AssumeNormalized())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func BenchmarkParseStrict(b *testing.B) {
+	const header = "max-age=3600,must-revalidate,private"
+	b.Run("default", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := cachecontrolheader.ParseStrict(header); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("AssumeNormalized", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := cachecontrolheader.ParseStrict(header, cachecontrolheader.AssumeNormalized()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestParseAndValidate(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    string
+	}{
+		{
+			header:     "max-age=60, public",
+			wantHeader: &cachecontrolheader.Header{MaxAge: durationPtr(60 * time.Second), Public: true},
+		},
+		{
+			header:  "no-store, public",
+			wantErr: "conflicting directives: no-store and public",
+		},
+		{
+			header:  "s-maxage=60, no-store, max-age=60, public",
+			wantErr: "conflicting directives: no-store and max-age, public, s-maxage",
+		},
+		{
+			header:  "no-store, max-age=invalid",
+			wantErr: `failed to parse the value of directive(max-age=invalid): invalid delta-seconds "invalid"`,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseAndValidate(tt.header)
+			var gotErr string
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("got error: %q, want: %q", gotErr, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseAndValidate_ParseError(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   []string
+	}{
+		{
+			header: "no-store, max-age=60, public",
+			want:   []string{"conflicting directives: no-store and max-age, public"},
+		},
+		{
+			header: "no-store, max-age=1h, public",
+			want: []string{
+				`failed to parse the value of directive(max-age=1h): invalid delta-seconds "1h"`,
+				"conflicting directives: no-store and public",
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			_, err := cachecontrolheader.ParseAndValidate(tt.header)
+			var pe *cachecontrolheader.ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("ParseAndValidate() error = %v, want a ParseError", err)
+			}
+			var got []string
+			for _, err := range pe.AllErrors() {
+				got = append(got, err.Error())
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseError.AllErrors() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseWithOptions(t *testing.T) {
+	t.Parallel()
+	o := cachecontrolheader.NewOptions(cachecontrolheader.IgnoreUnknownDirectives(), cachecontrolheader.RejectConflicts())
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			header:     "max-age=60, unknown",
+			wantHeader: &cachecontrolheader.Header{MaxAge: durationPtr(60 * time.Second)},
+		},
+		{
+			header:  "max-age=invalid",
+			wantErr: true,
+		},
+		{
+			header:  "no-store, public",
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseWithOptions(tt.header, o)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func BenchmarkParseWithOptions(b *testing.B) {
+	const header = "max-age=3600,must-revalidate,private,x-ext"
+	b.Run("ParseStrict", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := cachecontrolheader.ParseStrict(header, cachecontrolheader.IgnoreUnknownDirectives(), cachecontrolheader.AssumeNormalized()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ParseWithOptions", func(b *testing.B) {
+		o := cachecontrolheader.NewOptions(cachecontrolheader.IgnoreUnknownDirectives(), cachecontrolheader.AssumeNormalized())
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := cachecontrolheader.ParseWithOptions(header, o); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestParseStrict_CaptureUnknownDirectives(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header  string
+		want    string
+		wantErr bool
+	}{
+		{
+			header: "max-age=3600, unknown, community=uci",
+			want:   "max-age=3600, unknown, community=uci",
+		},
+		{
+			header: "unknown=10",
+			want:   "unknown=10",
+		},
+		{
+			header:  "max-age=invalid, unknown",
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := h.String(); got != tt.want {
+				t.Errorf("Header.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_Extensions(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		want       []cachecontrolheader.Extension
+		wantString string
+	}{
+		{
+			header: `X-Flag, x-key=, x-token=abc, x-quoted="a \"b\""`,
+			want: []cachecontrolheader.Extension{
+				{Name: "X-Flag"},
+				{Name: "x-key", HasValue: true},
+				{Name: "x-token", Value: "abc", HasValue: true},
+				{Name: "x-quoted", Value: `a "b"`, HasValue: true},
+			},
+			wantString: `x-flag, x-key=, x-token=abc, x-quoted="a \"b\""`,
+		},
+		{
+			header:     `x-empty=""`,
+			want:       []cachecontrolheader.Extension{{Name: "x-empty", HasValue: true}},
+			wantString: `x-empty=""`,
+		},
+		{
+			header:     "max-age=60",
+			want:       nil,
+			wantString: "max-age=60",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, h.Extensions()); diff != "" {
+				t.Errorf("Header.Extensions() mismatch (-want +got):\n%s", diff)
+			}
+			if got := h.String(); got != tt.wantString {
+				t.Errorf("Header.String() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestHeader_String_extensionValues(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   string
+	}{
+		{header: `x-token="a b"`, want: `x-token="a b"`},
+		{header: `x-token="a, b", max-age=60`, want: `max-age=60, x-token="a, b"`},
+		{header: `x-quoted="a \"b\" \\c"`, want: `x-quoted="a \"b\" \\c"`},
+		{header: `x-token="abc"`, want: `x-token="abc"`},
+		{header: "x-token=abc", want: "x-token=abc"},
+		{header: "x-token=a b", want: `x-token="a b"`},
+		{header: "x-token=a;b", want: `x-token="a;b"`},
+		{header: `x-token=a\b`, want: `x-token="a\\b"`},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := h.String()
+			if got != tt.want {
+				t.Errorf("Header.String() = %q, want %q", got, tt.want)
+			}
+			again, err := cachecontrolheader.ParseStrict(got, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatalf("ParseStrict(Header.String()) got error: %v", err)
+			}
+			if diff := cmp.Diff(h.Extensions(), again.Extensions()); diff != "" {
+				t.Errorf("Extensions mismatch after round trip (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeader_String_extensionRoundTrip(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   cachecontrolheader.Extension
+	}{
+		{header: "x-special", want: cachecontrolheader.Extension{Name: "x-special"}},
+		{header: "x-special=", want: cachecontrolheader.Extension{Name: "x-special", HasValue: true}},
+		{header: "x-special=1", want: cachecontrolheader.Extension{Name: "x-special", Value: "1", HasValue: true}},
+		{header: `x-special="a b"`, want: cachecontrolheader.Extension{Name: "x-special", Value: "a b", HasValue: true}},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := h.String(); got != tt.header {
+				t.Errorf("Header.String() = %q, want %q", got, tt.header)
+			}
+			again, err := cachecontrolheader.ParseStrict(h.String(), cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]cachecontrolheader.Extension{tt.want}, again.Extensions()); diff != "" {
+				t.Errorf("Extensions mismatch after round trip (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeader_RemoveExtension(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header    string
+		name      string
+		want      string
+		wantOrder []string
+	}{
+		{
+			header:    "max-age=60, x-a, x-b=1",
+			name:      "x-a",
+			want:      "max-age=60, x-b=1",
+			wantOrder: []string{"max-age", "x-b"},
+		},
+		{
+			header:    "max-age=60, x-a, x-b=1",
+			name:      "X-B",
+			want:      "max-age=60, x-a",
+			wantOrder: []string{"max-age", "x-a"},
+		},
+		{
+			header:    "max-age=60, x-a",
+			name:      "x-missing",
+			want:      "max-age=60, x-a",
+			wantOrder: []string{"max-age", "x-a"},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header+"/"+tt.name, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			h.RemoveExtension(tt.name)
+			if got := h.String(); got != tt.want {
+				t.Errorf("Header.String() = %q, want %q", got, tt.want)
+			}
+			if diff := cmp.Diff(tt.wantOrder, h.DirectiveOrder()); diff != "" {
+				t.Errorf("Header.DirectiveOrder() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeader_RemoveExtension_copy(t *testing.T) {
+	t.Parallel()
+	h, err := cachecontrolheader.ParseStrict("x-a, x-b", cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := *h
+	c.RemoveExtension("x-a")
+	if got, want := c.String(), "x-b"; got != want {
+		t.Errorf("copy: Header.String() = %q, want %q", got, want)
+	}
+	if got, want := h.String(), "x-a, x-b"; got != want {
+		t.Errorf("original: Header.String() = %q, want %q", got, want)
+	}
+}
+
+func TestHeader_SetPublic_SetPrivate(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name   string
+		header string
+		set    func(h *cachecontrolheader.Header)
+		want   string
+	}{
+		{name: "SetPublic clears private", header: "private, max-age=60", set: (*cachecontrolheader.Header).SetPublic, want: "max-age=60, public"},
+		{name: "SetPublic keeps qualified private", header: `private="Set-Cookie"`, set: (*cachecontrolheader.Header).SetPublic, want: `private="Set-Cookie", public`},
+		{name: "SetPublic on empty", header: "", set: (*cachecontrolheader.Header).SetPublic, want: "public"},
+		{name: "SetPrivate clears public", header: "public, s-maxage=60", set: (*cachecontrolheader.Header).SetPrivate, want: "private, s-maxage=60"},
+		{name: "SetPrivate on empty", header: "", set: (*cachecontrolheader.Header).SetPrivate, want: "private"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := cachecontrolheader.Parse(tt.header)
+			tt.set(h)
+			if got := h.String(); got != tt.want {
+				t.Errorf("Header.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_RoundDurations(t *testing.T) {
+	t.Parallel()
+	h := &cachecontrolheader.Header{}
+	h.SetMaxAge(1500 * time.Millisecond)
+	h.SetSMaxAge(1499 * time.Millisecond)
+	h.SetMinFresh(3 * time.Second)
+	d := 250 * time.Millisecond
+	h.MaxStale = &d
+	h.RoundDurations()
+
+	want := &cachecontrolheader.Header{
+		MaxAge:   durationPtr(2 * time.Second),
+		MaxStale: durationPtr(0),
+		MinFresh: durationPtr(3 * time.Second),
+		SMaxAge:  durationPtr(time.Second),
+	}
+	if diff := cmp.Diff(want, h, ignoreUnexported); diff != "" {
+		t.Errorf("Header mismatch (-want +got):\n%s", diff)
+	}
+	if d != 250*time.Millisecond {
+		t.Errorf("the duration set before was modified: %v", d)
+	}
+	reparsed, err := cachecontrolheader.ParseStrict(h.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(h, reparsed, ignoreUnexported); diff != "" {
+		t.Errorf("reparsed Header mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHeader_ClearExtensions(t *testing.T) {
+	t.Parallel()
+	h, err := cachecontrolheader.ParseStrict("max-age=60, x-a, x-b=1", cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.ClearExtensions()
+	if got, want := h.String(), "max-age=60"; got != want {
+		t.Errorf("Header.String() = %q, want %q", got, want)
+	}
+	h.ClearExtensions()
+	if got, want := h.String(), "max-age=60"; got != want {
+		t.Errorf("Header.String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseStrict_RejectConflicts(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			header:  "no-store, max-age=60",
+			wantErr: true,
+		},
+		{
+			header:  "public, no-store",
+			wantErr: true,
+		},
+		{
+			header:  "no-store, s-maxage=60",
+			wantErr: true,
+		},
+		{
+			header: "no-store, must-revalidate",
+			wantHeader: &cachecontrolheader.Header{
+				NoStore:        true,
+				MustRevalidate: true,
+			},
+		},
+		{
+			header: "max-age=60, public",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(60 * time.Second),
+				Public: true,
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.RejectConflicts())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeader_CachingDisabled(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   bool
+	}{
+		{header: "no-store", want: true},
+		{header: "no-store, max-age=3600", want: true},
+		{header: "no-cache, max-age=0", want: true},
+		{header: "no-cache", want: false},
+		{header: "max-age=0", want: false},
+		{header: "no-cache, max-age=60", want: false},
+		{header: "max-age=3600, public", want: false},
+		{header: "", want: false},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.Parse(tt.header).CachingDisabled(); got != tt.want {
+				t.Errorf("Header.CachingDisabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_StringPreserveOrder(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   string
+	}{
+		{
+			header: "private, must-revalidate, max-age=3600",
+			want:   "private, must-revalidate, max-age=3600",
+		},
+		{
+			header: "x-a, s-maxage=60, x-b=1, no-cache",
+			want:   "x-a, s-maxage=60, x-b=1, no-cache",
+		},
+		{
+			header: "max-age=60, public, max-age=30",
+			want:   "max-age=30, public",
+		},
+		{
+			header: "public, max-age=invalid, private",
+			want:   "public, private",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.IgnoreInvalidValues(), cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := h.StringPreserveOrder(); got != tt.want {
+				t.Errorf("Header.StringPreserveOrder() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_StringPreserveOrder_handBuilt(t *testing.T) {
+	t.Parallel()
+	h := &cachecontrolheader.Header{
+		Private: true,
+		MaxAge:  durationPtr(60 * time.Second),
+	}
+	if got, want := h.StringPreserveOrder(), "max-age=60, private"; got != want {
+		t.Errorf("Header.StringPreserveOrder() = %q, want %q", got, want)
+	}
+
+	h = cachecontrolheader.Parse("private, no-cache")
+	h.NoCache = false
+	h.MaxAge = durationPtr(60 * time.Second)
+	if got, want := h.StringPreserveOrder(), "private, max-age=60"; got != want {
+		t.Errorf("Header.StringPreserveOrder() = %q, want %q", got, want)
+	}
+}
+
+// cancelAfterContext is a context that gets canceled after its Err method is called n times.
+type cancelAfterContext struct {
+	context.Context
+	n int
+}
+
+func (c *cancelAfterContext) Err() error {
+	if c.n <= 0 {
+		return context.Canceled
+	}
+	c.n--
+	return nil
+}
+
+func TestParseContext(t *testing.T) {
+	t.Parallel()
+	t.Run("not canceled", func(t *testing.T) {
+		t.Parallel()
+		h, err := cachecontrolheader.ParseContext(context.Background(), "max-age=60, private")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := h.String(), "max-age=60, private"; got != want {
+			t.Errorf("Header.String() = %q, want %q", got, want)
+		}
+	})
+	t.Run("canceled mid-parse", func(t *testing.T) {
+		t.Parallel()
+		header := strings.Repeat("public, ", 100000) + "private"
+		ctx := &cancelAfterContext{Context: context.Background(), n: 3}
+		h, err := cachecontrolheader.ParseContext(ctx, header)
+		if err != context.Canceled {
+			t.Errorf("got error: %v, want: %v", err, context.Canceled)
+		}
+		if h != nil {
+			t.Errorf("got header: %v, want: nil", h)
+		}
+	})
+	t.Run("canceled before parse", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := cachecontrolheader.ParseContext(ctx, "max-age=60"); err != context.Canceled {
+			t.Errorf("got error: %v, want: %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestHeader_setters(t *testing.T) {
+	t.Parallel()
+	h := &cachecontrolheader.Header{}
+	h.SetMaxAge(60 * time.Second)
+	h.SetMaxStale(10 * time.Second)
+	h.SetMinFresh(5 * time.Second)
+	h.SetSMaxAge(0)
+	want := &cachecontrolheader.Header{
+		MaxAge:   durationPtr(60 * time.Second),
+		MaxStale: durationPtr(10 * time.Second),
+		MinFresh: durationPtr(5 * time.Second),
+		SMaxAge:  durationPtr(0),
+	}
+	if diff := cmp.Diff(want, h, ignoreUnexported); diff != "" {
+		t.Errorf("Header mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := h.String(), "max-age=60, max-stale=10, min-fresh=5, s-maxage=0"; got != want {
+		t.Errorf("Header.String() = %q, want %q", got, want)
+	}
+
+	h.ClearMaxAge()
+	h.ClearMaxStale()
+	h.ClearMinFresh()
+	h.ClearSMaxAge()
+	if diff := cmp.Diff(&cachecontrolheader.Header{}, h, ignoreUnexported); diff != "" {
+		t.Errorf("Header mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHeader_StringHuman(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   string
+	}{
+		{header: "max-age=3600", want: "max-age=1h0m0s"},
+		{header: "max-age=0, s-maxage=90, public, x-ext=1", want: "max-age=0s, public, s-maxage=1m30s, x-ext=1"},
+		{header: "no-store", want: "no-store"},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := h.StringHuman(); got != tt.want {
+				t.Errorf("Header.StringHuman() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_StringFolded(t *testing.T) {
+	t.Parallel()
+	h, err := cachecontrolheader.ParseStrict("max-age=60, public, x-aaaa=1, x-bbbb=2, x-cccc=3, x-dddd=4, x-eeeeeeeeeeeeeeeeeeeeeeee=5", cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		maxLen int
+		want   string
+	}{
+		{
+			maxLen: 30,
+			want: "max-age=60, public, x-aaaa=1,\r\n" +
+				" x-bbbb=2, x-cccc=3, x-dddd=4,\r\n" +
+				" x-eeeeeeeeeeeeeeeeeeeeeeee=5",
+		},
+		{
+			maxLen: 20,
+			want: "max-age=60, public,\r\n" +
+				" x-aaaa=1, x-bbbb=2,\r\n" +
+				" x-cccc=3, x-dddd=4,\r\n" +
+				" x-eeeeeeeeeeeeeeeeeeeeeeee=5",
+		},
+		{
+			maxLen: 0,
+			want:   h.String(),
+		},
+		{
+			maxLen: 1000,
+			want:   h.String(),
+		},
+	} {
+		tt := tt
+		t.Run(strconv.Itoa(tt.maxLen), func(t *testing.T) {
+			t.Parallel()
+			got := h.StringFolded(tt.maxLen)
+			if got != tt.want {
+				t.Errorf("Header.StringFolded(%d) = %q, want %q", tt.maxLen, got, tt.want)
+			}
+			reparsed, err := cachecontrolheader.ParseStrict(got, cachecontrolheader.CaptureUnknownDirectives(), cachecontrolheader.IgnoreInvalidValues())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := reparsed.String(); s != h.String() {
+				t.Errorf("unfolded header = %q, want %q", s, h.String())
+			}
+		})
+	}
+}
+
+func TestHeader_WriteString_AppendTo(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []string{
+		"",
+		"no-store",
+		`max-age=60, no-cache="Set-Cookie", private, x-ext="a b"`,
+	} {
+		tt := tt
+		t.Run(tt, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := h.String()
+
+			var buf bytes.Buffer
+			buf.WriteString("Cache-Control: ")
+			n, err := h.WriteString(&buf)
+			if err != nil {
+				t.Fatalf("Header.WriteString() got error: %v", err)
+			}
+			if got := buf.String(); got != "Cache-Control: "+want {
+				t.Errorf("Header.WriteString() wrote %q, want %q", got, "Cache-Control: "+want)
+			}
+			if n != len(want) {
+				t.Errorf("Header.WriteString() = %d, want %d", n, len(want))
+			}
+
+			if got := string(h.AppendTo([]byte("Cache-Control: "))); got != "Cache-Control: "+want {
+				t.Errorf("Header.AppendTo() = %q, want %q", got, "Cache-Control: "+want)
+			}
+		})
+	}
+}
+
+func TestHeader_WriteString_error(t *testing.T) {
+	t.Parallel()
+	h := cachecontrolheader.Parse("max-age=60, public")
+	w := &limitedWriter{n: 12}
+	n, err := h.WriteString(w)
+	if err == nil {
+		t.Error("Header.WriteString() got no error")
+	}
+	if n != 12 {
+		t.Errorf("Header.WriteString() = %d, want %d", n, 12)
+	}
+}
+
+// limitedWriter accepts n bytes and fails afterwards.
+type limitedWriter struct {
+	n int
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errors.New("short write")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestHeader_StringWith_PreserveExtensionCase(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header   string
+		want     string
+		wantCase string
+	}{
+		{
+			header:   "Max-Age=60, X-Vendor-Flag, X-Token=AbC",
+			want:     "max-age=60, x-vendor-flag, x-token=AbC",
+			wantCase: "max-age=60, X-Vendor-Flag, X-Token=AbC",
+		},
+		{
+			header:   "x-lower",
+			want:     "x-lower",
+			wantCase: "x-lower",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := h.String(); got != tt.want {
+				t.Errorf("Header.String() = %q, want %q", got, tt.want)
+			}
+			if got := h.StringWith(); got != tt.want {
+				t.Errorf("Header.StringWith() = %q, want %q", got, tt.want)
+			}
+			if got := h.StringWith(cachecontrolheader.PreserveExtensionCase()); got != tt.wantCase {
+				t.Errorf("Header.StringWith(PreserveExtensionCase()) = %q, want %q", got, tt.wantCase)
+			}
+		})
+	}
+}
+
+func TestHeader_StringWith_SortExtensions(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   string
+	}{
+		{
+			header: "x-zeta=1, max-age=60, X-Beta, x-alpha=\"a, b\"",
+			want:   `max-age=60, x-alpha="a, b", x-beta, x-zeta=1`,
+		},
+		{
+			header: "x-b=2, x-a, x-b=1",
+			want:   "x-a, x-b=2, x-b=1",
+		},
+		{
+			header: "no-store",
+			want:   "no-store",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := h.String()
+			if got := h.StringWith(cachecontrolheader.SortExtensions()); got != tt.want {
+				t.Errorf("Header.StringWith(SortExtensions()) = %q, want %q", got, tt.want)
+			}
+			if got := h.String(); got != before {
+				t.Errorf("Header.String() changed to %q, want %q", got, before)
+			}
+		})
+	}
+}
+
+func TestHeader_StringFiltered(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		allow  []string
+		want   string
+	}{
+		{
+			header: `max-age=60, no-cache="Set-Cookie", private, public, s-maxage=30, x-ext=1`,
+			allow:  []string{"max-age", "public"},
+			want:   "max-age=60, public",
+		},
+		{
+			header: "Max-Age=60, X-Ext=1, x-other",
+			allow:  []string{"MAX-AGE", "x-ext"},
+			want:   "max-age=60, x-ext=1",
+		},
+		{
+			header: "no-store",
+			allow:  []string{"max-age", "public"},
+			want:   "",
+		},
+		{
+			header: "max-age=60, public",
+			allow:  nil,
+			want:   "",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := h.StringFiltered(tt.allow); got != tt.want {
+				t.Errorf("Header.StringFiltered(%q) = %q, want %q", tt.allow, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_StringWith_PriorityDirectives(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header   string
+		priority []string
+		want     string
+	}{
+		{
+			header:   "max-age=0, must-revalidate, no-store, private",
+			priority: []string{"no-store", "private"},
+			want:     "no-store, private, max-age=0, must-revalidate",
+		},
+		{
+			header:   "max-age=0, no-store, private",
+			priority: []string{"Private", "public", "no-store"},
+			want:     "private, no-store, max-age=0",
+		},
+		{
+			header:   "max-age=60, x-first",
+			priority: []string{"x-first"},
+			want:     "x-first, max-age=60",
+		},
+		{
+			header: "max-age=60, no-store",
+			want:   "max-age=60, no-store",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := h.StringWith(cachecontrolheader.PriorityDirectives(tt.priority...)); got != tt.want {
+				t.Errorf("Header.StringWith(PriorityDirectives(%q...)) = %q, want %q", tt.priority, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStrict_quotedString(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantString string
+		wantErr    bool
+	}{
+		{
+			header: `private="Set-Cookie, Authorization", max-age=60`,
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:        durationPtr(60 * time.Second),
+				PrivateFields: []string{"Set-Cookie", "Authorization"},
+			},
+			wantString: `max-age=60, private="Set-Cookie, Authorization"`,
+		},
+		{
+			header: `no-cache="a\"b, c\\d", no-store`,
+			wantHeader: &cachecontrolheader.Header{
+				NoCacheFields: []string{`a"b`, `c\d`},
+				NoStore:       true,
+			},
+			wantString: `no-cache="a\"b, c\\d", no-store`,
+		},
+		{
+			header: `private, private="X-Secret"`,
+			wantHeader: &cachecontrolheader.Header{
+				Private:       true,
+				PrivateFields: []string{"X-Secret"},
+			},
+			wantString: `private, private="X-Secret"`,
+		},
+		{
+			header: `max-age="60"`,
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(60 * time.Second),
+			},
+			wantString: "max-age=60",
+		},
+		{
+			header: "max-age=60 ,\tpublic,, ,",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(60 * time.Second),
+				Public: true,
+			},
+			wantString: "max-age=60, public",
+		},
+		{
+			header:  `private="a\"b`,
+			wantErr: true,
+		},
+		{
+			header:  `private="a"b`,
+			wantErr: true,
+		},
+		{
+			header:  `private=""`,
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if got := h.String(); got != tt.wantString {
+				t.Errorf("Header.String() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestParseStrict_fieldNamesOrder(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantFields []string
+		wantString string
+	}{
+		{
+			header:     `private="Set-Cookie, Authorization, Age"`,
+			wantFields: []string{"Set-Cookie", "Authorization", "Age"},
+			wantString: `private="Set-Cookie, Authorization, Age"`,
+		},
+		{
+			header:     `private="  X-Z ,X-A,  X-M  "`,
+			wantFields: []string{"X-Z", "X-A", "X-M"},
+			wantString: `private="X-Z, X-A, X-M"`,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.wantFields, h.PrivateFields); diff != "" {
+				t.Errorf("PrivateFields mismatch (-want +got):\n%s", diff)
+			}
+			s := h.String()
+			if s != tt.wantString {
+				t.Errorf("Header.String() = %q, want %q", s, tt.wantString)
+			}
+			reparsed, err := cachecontrolheader.ParseStrict(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.wantFields, reparsed.PrivateFields); diff != "" {
+				t.Errorf("PrivateFields after round trip mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_quotedString(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   *cachecontrolheader.Header
+	}{
+		{
+			header: `private="a\"b, max-age=60`,
+			want:   &cachecontrolheader.Header{},
+		},
+		{
+			header: `private="a"b, max-age=60`,
+			want: &cachecontrolheader.Header{
+				MaxAge: durationPtr(60 * time.Second),
+			},
+		},
+		{
+			header: `private=""`,
+			want: &cachecontrolheader.Header{
+				Private: true,
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h := cachecontrolheader.Parse(tt.header)
+			if diff := cmp.Diff(tt.want, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseStrict_CaptureUnknownDirectives_quotedString(t *testing.T) {
+	t.Parallel()
+	h, err := cachecontrolheader.ParseStrict(`x-list="a, b", max-age=60`, cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.String(), `max-age=60, x-list="a, b"`; got != want {
+		t.Errorf("Header.String() = %q, want %q", got, want)
+	}
+}
+
+func TestHeader_AlwaysRevalidate(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   bool
+	}{
+		{header: "max-age=0, must-revalidate", want: true},
+		{header: "no-cache", want: true},
+		{header: "no-cache, max-age=3600", want: true},
+		{header: "max-age=0", want: false},
+		{header: "must-revalidate", want: false},
+		{header: "max-age=60, must-revalidate", want: false},
+		{header: `no-cache="Set-Cookie"`, want: false},
+		{header: "no-store", want: false},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.Parse(tt.header).AlwaysRevalidate(); got != tt.want {
+				t.Errorf("Header.AlwaysRevalidate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_IsPrivate_IsNoCache(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header        string
+		wantIsPrivate bool
+		wantIsNoCache bool
+	}{
+		{header: "", wantIsPrivate: false, wantIsNoCache: false},
+		{header: "private", wantIsPrivate: true, wantIsNoCache: false},
+		{header: `private="Set-Cookie"`, wantIsPrivate: true, wantIsNoCache: false},
+		{header: "no-cache", wantIsPrivate: false, wantIsNoCache: true},
+		{header: `no-cache="Set-Cookie, Authorization"`, wantIsPrivate: false, wantIsNoCache: true},
+		{header: `private, no-cache="Set-Cookie"`, wantIsPrivate: true, wantIsNoCache: true},
+		{header: "public, no-store", wantIsPrivate: false, wantIsNoCache: false},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h := cachecontrolheader.Parse(tt.header)
+			if got := h.IsPrivate(); got != tt.wantIsPrivate {
+				t.Errorf("Header.IsPrivate() = %v, want %v", got, tt.wantIsPrivate)
+			}
+			if got := h.IsNoCache(); got != tt.wantIsNoCache {
+				t.Errorf("Header.IsNoCache() = %v, want %v", got, tt.wantIsNoCache)
+			}
+		})
+	}
+}
+
+func TestHeader_RevalidationTarget(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantWhole  bool
+		wantFields []string
+	}{
+		{header: "no-cache", wantWhole: true},
+		{header: `no-cache, no-cache="Set-Cookie"`, wantWhole: true},
+		{header: "max-age=0, must-revalidate", wantWhole: true},
+		{header: `no-cache="Set-Cookie, Authorization"`, wantFields: []string{"Set-Cookie", "Authorization"}},
+		{header: `max-age=60, must-revalidate, no-cache="Set-Cookie"`, wantFields: []string{"Set-Cookie"}},
+		{header: "max-age=60, must-revalidate"},
+		{header: ""},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h := cachecontrolheader.Parse(tt.header)
+			whole, fields := h.RevalidationTarget()
+			if whole != tt.wantWhole {
+				t.Errorf("Header.RevalidationTarget() whole = %v, want %v", whole, tt.wantWhole)
+			}
+			if diff := cmp.Diff(tt.wantFields, fields); diff != "" {
+				t.Errorf("Header.RevalidationTarget() fields mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_bracketedDeltaSeconds(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+	}{
+		{
+			header:     "max-age=(60)",
+			wantHeader: &cachecontrolheader.Header{MaxAge: durationPtr(60 * time.Second)},
+		},
+		{
+			header:     "s-maxage=[30], public",
+			wantHeader: &cachecontrolheader.Header{SMaxAge: durationPtr(30 * time.Second), Public: true},
+		},
+		{
+			header:     "max-age=((60))",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+		{
+			header:     "max-age=(60]",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.wantHeader, cachecontrolheader.Parse(tt.header), ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if _, err := cachecontrolheader.ParseStrict(tt.header); err == nil {
+				t.Error("ParseStrict() got no error")
+			}
+		})
+	}
+}
+
+func TestParseStrict_ClampNegativeToZero(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			header: "max-stale=-1, min-fresh=-30",
+			wantHeader: &cachecontrolheader.Header{
+				MaxStale: durationPtr(0),
+				MinFresh: durationPtr(0),
+			},
+		},
+		{
+			header:     "max-age=-1, max-stale=-1",
+			wantHeader: &cachecontrolheader.Header{MaxStale: durationPtr(0)},
+		},
+		{
+			header:     "max-stale=-1s, min-fresh=-",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.IgnoreInvalidValues(), cachecontrolheader.ClampNegativeToZero())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if _, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.ClampNegativeToZero()); err == nil {
+				t.Error("ParseStrict() without IgnoreInvalidValues got no error")
+			}
+		})
+	}
+}
+
+func TestParseStrict_DuplicatePolicy(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name       string
+		mode       cachecontrolheader.DuplicateMode
+		wantHeader *cachecontrolheader.Header
+	}{
+		{
+			name: "last wins",
+			mode: cachecontrolheader.DuplicateLastWins,
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:   durationPtr(30 * time.Second),
+				MinFresh: durationPtr(5 * time.Second),
+			},
+		},
+		{
+			name: "first wins",
+			mode: cachecontrolheader.DuplicateFirstWins,
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:   durationPtr(60 * time.Second),
+				MinFresh: durationPtr(10 * time.Second),
+			},
+		},
+		{
+			name: "most restrictive",
+			mode: cachecontrolheader.DuplicateMostRestrictive,
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:   durationPtr(30 * time.Second),
+				MinFresh: durationPtr(10 * time.Second),
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict("max-age=60, min-fresh=10, max-age=30, min-fresh=5", cachecontrolheader.DuplicatePolicy(tt.mode))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseStrict_obsoleteDirectives(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantString string
+	}{
+		{header: "no-cache, post-check=0, pre-check=0", wantString: "no-cache, post-check=0, pre-check=0"},
+		{header: "Pre-Check=900, max-age=60", wantString: "max-age=60, pre-check=900"},
+		{header: "post-check", wantString: "post-check"},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header)
+			if err != nil {
+				t.Fatalf("ParseStrict() got error: %v", err)
+			}
+			if got := h.String(); got != tt.wantString {
+				t.Errorf("Header.String() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
+
+func TestParse_multipleDeltaSeconds(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+	}{
+		{
+			header:     "max-age=60 120",
+			wantHeader: &cachecontrolheader.Header{MaxAge: durationPtr(60 * time.Second)},
+		},
+		{
+			header:     "s-maxage=30\t 10 5, public",
+			wantHeader: &cachecontrolheader.Header{SMaxAge: durationPtr(30 * time.Second), Public: true},
+		},
+		{
+			header:     "max-age=60 abc",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.wantHeader, cachecontrolheader.Parse(tt.header), ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if _, err := cachecontrolheader.ParseStrict(tt.header); err == nil {
+				t.Error("ParseStrict() got no error")
+			}
+		})
+	}
+}
+
+func TestParseStrict_DisallowDuplicates(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header               string
+		wantErr              bool
+		wantErrAllowBooleans bool
+	}{
+		{header: "no-store, max-age=60"},
+		{header: "no-store, no-store", wantErr: true},
+		{header: `no-cache, no-cache="Set-Cookie"`},
+		{header: "max-age=60, max-age=30", wantErr: true, wantErrAllowBooleans: true},
+		{header: "max-age=60, Max-Age=60", wantErr: true, wantErrAllowBooleans: true},
+		{header: "max-age=60, Max-Age=120", wantErr: true, wantErrAllowBooleans: true},
+		{header: "S-MAXAGE=10, s-maxage=20", wantErr: true, wantErrAllowBooleans: true},
+		{header: "NO-STORE, no-store", wantErr: true},
+		{header: `Private="A", PRIVATE="B"`, wantErr: true, wantErrAllowBooleans: true},
+		{header: `private="A", private="B"`, wantErr: true, wantErrAllowBooleans: true},
+		{header: "x-ext=1, x-ext=2"},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if _, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.IgnoreUnknownDirectives()); err != nil {
+				t.Errorf("ParseStrict() got error: %v", err)
+			}
+			_, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.IgnoreUnknownDirectives(), cachecontrolheader.DisallowDuplicates())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseStrict(DisallowDuplicates()) got error: %v, want: %v", err, tt.wantErr)
+			}
+			_, err = cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.IgnoreUnknownDirectives(), cachecontrolheader.DisallowDuplicates(), cachecontrolheader.AllowDuplicateBooleans())
+			if (err != nil) != tt.wantErrAllowBooleans {
+				t.Errorf("ParseStrict(DisallowDuplicates(), AllowDuplicateBooleans()) got error: %v, want: %v", err, tt.wantErrAllowBooleans)
+			}
+		})
+	}
+}
+
+func TestParseStrict_deltaSeconds(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    string
+	}{
+		{
+			header: "max-age=10",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(10 * time.Second),
+			},
+		},
+		{
+			header: "max-age=0",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(0),
+			},
+		},
+		{
+			header: "max-age=99999999999999999999",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr((1 << 31) * time.Second),
+			},
+		},
+		{
+			header:  "max-age=1h",
+			wantErr: `failed to parse the value of directive(max-age=1h): invalid delta-seconds "1h"`,
+		},
+		{
+			header:  "max-age=3m",
+			wantErr: `failed to parse the value of directive(max-age=3m): invalid delta-seconds "3m"`,
+		},
+		{
+			header:  "max-stale=-1",
+			wantErr: `failed to parse the value of directive(max-stale=-1): invalid delta-seconds "-1"`,
+		},
+		{
+			header:  "min-fresh=+1",
+			wantErr: `failed to parse the value of directive(min-fresh=+1): invalid delta-seconds "+1"`,
+		},
+		{
+			header:  "s-maxage=1.5",
+			wantErr: `failed to parse the value of directive(s-maxage=1.5): invalid delta-seconds "1.5"`,
+		},
+		{
+			header:  "max-age=",
+			wantErr: "failed to parse the value of directive(max-age=): empty delta-seconds",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header)
+			var gotErr string
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("got error: %q, want: %q", gotErr, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseStrict_whitespaceOnlyValue(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header  string
+		wantErr string
+	}{
+		{
+			header:  "max-age= ",
+			wantErr: "failed to parse the value of directive(max-age=): empty delta-seconds",
+		},
+		{
+			header:  "s-maxage=\t",
+			wantErr: "failed to parse the value of directive(s-maxage=): empty delta-seconds",
+		},
+		{
+			header:  `max-age=" ", public`,
+			wantErr: `failed to parse the value of directive(max-age=" "): empty delta-seconds`,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			_, err := cachecontrolheader.ParseStrict(tt.header)
+			var ive *cachecontrolheader.InvalidValueError
+			if !errors.As(err, &ive) {
+				t.Fatalf("got error: %v, want *InvalidValueError", err)
+			}
+			if got := err.Error(); got != tt.wantErr {
+				t.Errorf("got error: %q, want: %q", got, tt.wantErr)
+			}
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.IgnoreInvalidValues())
+			if err != nil {
+				t.Fatalf("ParseStrict(IgnoreInvalidValues()) got error: %v", err)
+			}
+			if h.MaxAge != nil || h.SMaxAge != nil {
+				t.Errorf("ParseStrict(IgnoreInvalidValues()) kept the directive: %q", h.String())
+			}
+		})
+	}
+}
+
+func TestParseStrict_RequireNonEmpty(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			header:  "",
+			wantErr: true,
+		},
+		{
+			header:  " \t ",
+			wantErr: true,
+		},
+		{
+			header:  " , ,",
+			wantErr: true,
+		},
+		{
+			header: "no-store",
+			wantHeader: &cachecontrolheader.Header{
+				NoStore: true,
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.RequireNonEmpty())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseStrict_MaxDirectives(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name    string
+		header  string
+		max     int
+		wantErr bool
+	}{
+		{name: "at the limit", header: "no-store, no-cache", max: 2},
+		{name: "empty elements not counted", header: "no-store, , no-cache,", max: 2},
+		{name: "over the limit", header: "no-store, no-cache, public", max: 2, wantErr: true},
+		{name: "unknown directives counted", header: "x, x, x, x, x", max: 4, wantErr: true},
+		{name: "zero is unlimited", header: strings.Repeat("x, ", 10000), max: 0},
+		{name: "negative is unlimited", header: strings.Repeat("x, ", 10000), max: -1},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.IgnoreUnknownDirectives(), cachecontrolheader.MaxDirectives(tt.max))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseStrict_ClampSMaxAgeToMaxAge(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+	}{
+		{
+			header: "max-age=60, s-maxage=3600",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(60 * time.Second),
+				SMaxAge: durationPtr(60 * time.Second),
+			},
+		},
+		{
+			header: "s-maxage=30, max-age=60",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(60 * time.Second),
+				SMaxAge: durationPtr(30 * time.Second),
+			},
+		},
+		{
+			header: "s-maxage=3600",
+			wantHeader: &cachecontrolheader.Header{
+				SMaxAge: durationPtr(3600 * time.Second),
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.ClampSMaxAgeToMaxAge())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseStrict_IgnoreUnknownDirectives_CaptureUnknownDirectives(t *testing.T) {
+	t.Parallel()
+	h, err := cachecontrolheader.ParseStrict("max-age=60, x-a, x-b=1", cachecontrolheader.IgnoreUnknownDirectives(), cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.String(), "max-age=60, x-a, x-b=1"; got != want {
+		t.Errorf("Header.String() = %q, want %q", got, want)
+	}
+
+	h, err = cachecontrolheader.ParseStrict("max-age=bad, x-a", cachecontrolheader.IgnoreUnknownDirectives(), cachecontrolheader.CaptureUnknownDirectives())
+	if err == nil {
+		t.Errorf("got error: %v, want: %v", err, true)
+	}
+	if h != nil {
+		t.Errorf("got header: %v, want: nil", h)
+	}
+}
+
+func TestHeader_DirectiveOrder(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   []string
+	}{
+		{
+			header: "max-age=60, No-Store, public",
+			want:   []string{"max-age", "no-store", "public"},
+		},
+		{
+			header: "public, max-age=60, x-ext, max-age=30, public",
+			want:   []string{"public", "max-age", "x-ext"},
+		},
+		{
+			header: "",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, h.DirectiveOrder()); diff != "" {
+				t.Errorf("DirectiveOrder mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeader_DirectiveOrder_manyDirectives(t *testing.T) {
+	t.Parallel()
+	var names []string
+	for i := 0; i < 100000; i++ {
+		names = append(names, "x-"+strconv.Itoa(i))
+	}
+	header := strings.Join(names, ", ")
+	h, err := cachecontrolheader.ParseStrict(header+", "+header+", max-age=60", cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := h.DirectiveOrder()
+	if diff := cmp.Diff(append(names, "max-age"), got); diff != "" {
+		t.Errorf("Header.DirectiveOrder() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHeader_StringPreserveOrder_manyDirectives(t *testing.T) {
+	t.Parallel()
+	var names, want []string
+	for i := 0; i < 100000; i++ {
+		name := "x-" + strconv.Itoa(i)
+		names = append(names, name)
+		want = append(want, name, name)
+	}
+	header := strings.Join(names, ", ")
+	h, err := cachecontrolheader.ParseStrict(header+", max-age=60, "+header, cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.StringPreserveOrder(), strings.Join(append(want, "max-age=60"), ", "); got != want {
+		t.Errorf("Header.StringPreserveOrder() = %.100q..., want %.100q...", got, want)
+	}
+}
+
+func TestParse_obsFold(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+	}{
+		{
+			header: "max-age=60,\r\n private",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(60 * time.Second),
+				Private: true,
+			},
+		},
+		{
+			header: "max-age=60,\r\n\t \tprivate,\r\n must-revalidate",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:         durationPtr(60 * time.Second),
+				Private:        true,
+				MustRevalidate: true,
+			},
+		},
+		{
+			header: `private="Set-Cookie,` + "\r\n " + `Authorization"`,
+			wantHeader: &cachecontrolheader.Header{
+				PrivateFields: []string{"Set-Cookie", "Authorization"},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h := cachecontrolheader.Parse(tt.header)
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if _, err := cachecontrolheader.ParseStrict(tt.header); err == nil {
+				t.Errorf("ParseStrict(%q) got no error, want error", tt.header)
+			}
+		})
+	}
+}
+
+func TestParseStrict_ReportNonCanonicalCase(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			header: "max-age=60, no-store",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(60 * time.Second),
+				NoStore: true,
+			},
+		},
+		{
+			header:  "Max-Age=60",
+			wantErr: true,
+		},
+		{
+			header:  "max-age=60, NO-STORE",
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.ReportNonCanonicalCase())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeader_StringWith_OmitRedundantSMaxAge(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header   string
+		want     string
+		wantOmit string
+	}{
+		{
+			header:   "max-age=60, s-maxage=60, public",
+			want:     "max-age=60, public, s-maxage=60",
+			wantOmit: "max-age=60, public",
+		},
+		{
+			header:   "max-age=60, s-maxage=600",
+			want:     "max-age=60, s-maxage=600",
+			wantOmit: "max-age=60, s-maxage=600",
+		},
+		{
+			header:   "s-maxage=60",
+			want:     "s-maxage=60",
+			wantOmit: "s-maxage=60",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h := cachecontrolheader.Parse(tt.header)
+			if got := h.StringWith(); got != tt.want {
+				t.Errorf("Header.StringWith() = %q, want %q", got, tt.want)
+			}
+			if got := h.StringWith(cachecontrolheader.OmitRedundantSMaxAge()); got != tt.wantOmit {
+				t.Errorf("Header.StringWith(OmitRedundantSMaxAge()) = %q, want %q", got, tt.wantOmit)
+			}
+		})
+	}
+}
+
+func TestParse_quotedEmptyBoolean(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+	}{
+		{
+			header:     `no-store=""`,
+			wantHeader: &cachecontrolheader.Header{NoStore: true},
+		},
+		{
+			header:     `public="", must-revalidate=""`,
+			wantHeader: &cachecontrolheader.Header{Public: true, MustRevalidate: true},
+		},
+		{
+			header:     `no-cache=""`,
+			wantHeader: &cachecontrolheader.Header{NoCache: true},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h := cachecontrolheader.Parse(tt.header)
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if _, err := cachecontrolheader.ParseStrict(tt.header); err == nil {
+				t.Errorf("ParseStrict(%q) got no error, want error", tt.header)
+			}
+		})
+	}
+}
+
+func TestParseStrict_PreserveRawOnError(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name   string
+		header string
+	}{
+		{name: "invalid value", header: "max-age=1h, public"},
+		{name: "unknown directive", header: "public, x-vendor=1"},
+		{name: "syntax error", header: `private="Set-Cookie`},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.PreserveRawOnError())
+			if err == nil {
+				t.Fatal("ParseStrict() error = nil, want an error")
+			}
+			want := &cachecontrolheader.Header{Raw: tt.header}
+			if diff := cmp.Diff(want, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		h, err := cachecontrolheader.ParseStrict("max-age=60", cachecontrolheader.PreserveRawOnError())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &cachecontrolheader.Header{MaxAge: durationPtr(60 * time.Second)}
+		if diff := cmp.Diff(want, h, ignoreUnexported); diff != "" {
+			t.Errorf("Header mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("ParseAndValidate", func(t *testing.T) {
+		t.Parallel()
+		header := "no-store, max-age=60"
+		h, err := cachecontrolheader.ParseAndValidate(header, cachecontrolheader.PreserveRawOnError())
+		if err == nil {
+			t.Fatal("ParseAndValidate() error = nil, want an error")
+		}
+		if h == nil || h.Raw != header {
+			t.Errorf("ParseAndValidate() = %+v, want Raw %q", h, header)
+		}
+	})
+}
+
+func TestParseStrict_TolerateMissingEquals(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+	}{
+		{
+			header:     "max-age 3600",
+			wantHeader: &cachecontrolheader.Header{MaxAge: durationPtr(3600 * time.Second)},
+		},
+		{
+			header:     "public, S-MaxAge\t60",
+			wantHeader: &cachecontrolheader.Header{SMaxAge: durationPtr(60 * time.Second), Public: true},
+		},
+		{
+			header:     "max-age 1h",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+		{
+			header:     "max-age 60 120",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+		{
+			header:     "no-store 1",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			got, err := cachecontrolheader.ParseStrict(tt.header,
+				cachecontrolheader.TolerateMissingEquals(),
+				cachecontrolheader.IgnoreInvalidValues(),
+				cachecontrolheader.IgnoreUnknownDirectives(),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.wantHeader, got, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if _, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.TolerateMissingEquals()); err == nil {
+				t.Error("ParseStrict() without IgnoreInvalidValues got no error")
+			}
+		})
+	}
+}
+
+func TestParseError_AllErrors(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{
+			name:   "every problem",
+			header: "max-age=1h, x-unknown, public, s-maxage=abc",
+			want: []string{
+				`failed to parse the value of directive(max-age=1h): invalid delta-seconds "1h"`,
+				"unknown directive: x-unknown",
+				`failed to parse the value of directive(s-maxage=abc): invalid delta-seconds "abc"`,
+			},
+		},
+		{
+			name:   "single problem",
+			header: "public, max-age=-1",
+			want: []string{
+				`failed to parse the value of directive(max-age=-1): invalid delta-seconds "-1"`,
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := cachecontrolheader.ParseStrict(tt.header)
+			var pe *cachecontrolheader.ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("ParseStrict() error = %v, want a ParseError", err)
+			}
+			if pe.Error() != tt.want[0] {
+				t.Errorf("ParseError.Error() = %q, want %q", pe.Error(), tt.want[0])
+			}
+			for i := 0; i < 2; i++ {
+				var got []string
+				for _, err := range pe.AllErrors() {
+					got = append(got, err.Error())
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("ParseError.AllErrors() mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestParseError_AllErrors_options(t *testing.T) {
+	t.Parallel()
+	allErrors := func(t *testing.T, err error) []string {
+		t.Helper()
+		var pe *cachecontrolheader.ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("error = %v, want a ParseError", err)
+		}
+		var got []string
+		for _, err := range pe.AllErrors() {
+			got = append(got, err.Error())
+		}
+		return got
+	}
+
+	t.Run("DisallowDuplicates", func(t *testing.T) {
+		t.Parallel()
+		_, err := cachecontrolheader.ParseStrict("max-age=60, x-a, Max-Age=30", cachecontrolheader.DisallowDuplicates())
+		want := []string{"unknown directive: x-a", "duplicate directive: max-age"}
+		if diff := cmp.Diff(want, allErrors(t, err)); diff != "" {
+			t.Errorf("ParseError.AllErrors() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("RequireNonEmpty", func(t *testing.T) {
+		t.Parallel()
+		_, err := cachecontrolheader.ParseStrict("x-a", cachecontrolheader.RequireNonEmpty())
+		want := []string{"unknown directive: x-a"}
+		if diff := cmp.Diff(want, allErrors(t, err)); diff != "" {
+			t.Errorf("ParseError.AllErrors() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("RequireNonEmpty empty", func(t *testing.T) {
+		t.Parallel()
+		_, err := cachecontrolheader.ParseStrict(" , ", cachecontrolheader.RequireNonEmpty())
+		want := []string{"empty header"}
+		if diff := cmp.Diff(want, allErrors(t, err)); diff != "" {
+			t.Errorf("ParseError.AllErrors() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("obs-fold", func(t *testing.T) {
+		t.Parallel()
+		_, err := cachecontrolheader.ParseStrict("max-age=60,\r\n x-a")
+		want := []string{"syntax error: obs-fold", "unknown directive: x-a"}
+		if diff := cmp.Diff(want, allErrors(t, err)); diff != "" {
+			t.Errorf("ParseError.AllErrors() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("large header", func(t *testing.T) {
+		t.Parallel()
+		_, err := cachecontrolheader.ParseStrict(strings.Repeat("public, ", 20000) + "bad")
+		want := []string{"unknown directive: bad"}
+		if diff := cmp.Diff(want, allErrors(t, err)); diff != "" {
+			t.Errorf("ParseError.AllErrors() mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("MaxDirectives", func(t *testing.T) {
+		t.Parallel()
+		_, err := cachecontrolheader.ParseStrict("public, x-a, max-age=60, private, no-store", cachecontrolheader.MaxDirectives(2))
+		want := []string{"unknown directive: x-a", "too many directives: more than 2"}
+		if diff := cmp.Diff(want, allErrors(t, err)); diff != "" {
+			t.Errorf("ParseError.AllErrors() mismatch (-want +got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package cachecontrolheader
+
+import (
+	"fmt"
+	"time"
+)
+
+// Class represents a caching tier of a response, returned by [Header.Classify].
+type Class int
+
+const (
+	// ClassUnspecified means the header has no explicit freshness lifetime,
+	// so caches fall back to heuristics.
+	ClassUnspecified Class = iota
+	// ClassUncacheable means the response must not be stored (no-store).
+	ClassUncacheable
+	// ClassRevalidateAlways means the response must be revalidated on every use
+	// (no-cache, or a freshness lifetime of zero).
+	ClassRevalidateAlways
+	// ClassShort means the freshness lifetime is shorter than the long threshold.
+	ClassShort
+	// ClassLong means the freshness lifetime reaches the long threshold.
+	ClassLong
+	// ClassImmutable means the response never changes while fresh
+	// (immutable, or a freshness lifetime reaching the immutable threshold).
+	ClassImmutable
+)
+
+// String returns a string representation of the class.
+func (c Class) String() string {
+	switch c {
+	case ClassUnspecified:
+		return "unspecified"
+	case ClassUncacheable:
+		return "uncacheable"
+	case ClassRevalidateAlways:
+		return "revalidate-always"
+	case ClassShort:
+		return "short"
+	case ClassLong:
+		return "long"
+	case ClassImmutable:
+		return "immutable"
+	default:
+		return fmt.Sprintf("Class(%d)", int(c))
+	}
+}
+
+// Default thresholds used by [Header.Classify].
+const (
+	DefaultLongThreshold      = 24 * time.Hour
+	DefaultImmutableThreshold = 365 * 24 * time.Hour
+)
+
+// Classify categorizes the header into a caching tier.
+// The freshness lifetime is max-age, or s-maxage if max-age is not set.
+// The first matching rule wins:
+//
+//  1. no-store: [ClassUncacheable]
+//  2. no-cache, or a lifetime of zero: [ClassRevalidateAlways]
+//  3. immutable, or a lifetime of at least the immutable threshold: [ClassImmutable]
+//  4. a lifetime of at least the long threshold: [ClassLong]
+//  5. any other lifetime: [ClassShort]
+//  6. no lifetime: [ClassUnspecified]
+//
+// The thresholds default to [DefaultLongThreshold] and [DefaultImmutableThreshold],
+// and can be changed by [LongThreshold] and [ImmutableThreshold] options.
+func (h *Header) Classify(opts ...classifyOption) Class {
+	c := classifyConfig{
+		longThreshold:      DefaultLongThreshold,
+		immutableThreshold: DefaultImmutableThreshold,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	lifetime := h.MaxAge
+	if lifetime == nil {
+		lifetime = h.SMaxAge
+	}
+	switch {
+	case h.NoStore:
+		return ClassUncacheable
+	case h.NoCache, lifetime != nil && *lifetime == 0:
+		return ClassRevalidateAlways
+	case h.Immutable, lifetime != nil && *lifetime >= c.immutableThreshold:
+		return ClassImmutable
+	case lifetime == nil:
+		return ClassUnspecified
+	case *lifetime >= c.longThreshold:
+		return ClassLong
+	default:
+		return ClassShort
+	}
+}
+
+// IsEffectivelyImmutable reports whether a response with the header can be cached forever,
+// i.e. it has the immutable directive or a freshness lifetime of at least the immutable threshold,
+// which defaults to [DefaultImmutableThreshold] (one year) and can be changed by the [ImmutableThreshold] option.
+// It is equivalent to comparing [Header.Classify] with [ClassImmutable],
+// so no-store and no-cache take precedence.
+func (h *Header) IsEffectivelyImmutable(opts ...classifyOption) bool {
+	return h.Classify(opts...) == ClassImmutable
+}
+
+// LongThreshold sets the freshness lifetime from which [Header.Classify] returns [ClassLong].
+func LongThreshold(d time.Duration) classifyOption {
+	return func(c *classifyConfig) {
+		c.longThreshold = d
+	}
+}
+
+// ImmutableThreshold sets the freshness lifetime from which [Header.Classify] returns [ClassImmutable].
+func ImmutableThreshold(d time.Duration) classifyOption {
+	return func(c *classifyConfig) {
+		c.immutableThreshold = d
+	}
+}
+
+type classifyConfig struct {
+	longThreshold      time.Duration
+	immutableThreshold time.Duration
+}
+type classifyOption func(*classifyConfig)
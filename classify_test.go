@@ -0,0 +1,101 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestHeader_Classify(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   cachecontrolheader.Class
+	}{
+		{header: "", want: cachecontrolheader.ClassUnspecified},
+		{header: "public", want: cachecontrolheader.ClassUnspecified},
+		{header: "no-store, max-age=3600", want: cachecontrolheader.ClassUncacheable},
+		{header: "no-cache", want: cachecontrolheader.ClassRevalidateAlways},
+		{header: "max-age=0", want: cachecontrolheader.ClassRevalidateAlways},
+		{header: "max-age=60", want: cachecontrolheader.ClassShort},
+		{header: "s-maxage=60", want: cachecontrolheader.ClassShort},
+		{header: "max-age=86400", want: cachecontrolheader.ClassLong},
+		{header: "max-age=31536000", want: cachecontrolheader.ClassImmutable},
+		{header: "max-age=60, immutable", want: cachecontrolheader.ClassImmutable},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.Parse(tt.header).Classify(); got != tt.want {
+				t.Errorf("Header.Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_Classify_thresholds(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   cachecontrolheader.Class
+	}{
+		{header: "max-age=59", want: cachecontrolheader.ClassShort},
+		{header: "max-age=60", want: cachecontrolheader.ClassLong},
+		{header: "max-age=3600", want: cachecontrolheader.ClassImmutable},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			got := cachecontrolheader.Parse(tt.header).Classify(
+				cachecontrolheader.LongThreshold(time.Minute),
+				cachecontrolheader.ImmutableThreshold(time.Hour),
+			)
+			if got != tt.want {
+				t.Errorf("Header.Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_IsEffectivelyImmutable(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   bool
+	}{
+		{header: "max-age=31536000", want: true},
+		{header: "max-age=31535999", want: false},
+		{header: "public, max-age=60, immutable", want: true},
+		{header: "immutable", want: true},
+		{header: "no-cache, immutable", want: false},
+		{header: "no-store, max-age=31536000", want: false},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.Parse(tt.header).IsEffectivelyImmutable(); got != tt.want {
+				t.Errorf("Header.IsEffectivelyImmutable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_IsEffectivelyImmutable_threshold(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   bool
+	}{
+		{header: "max-age=86400", want: true},
+		{header: "max-age=86399", want: false},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.Parse(tt.header).IsEffectivelyImmutable(cachecontrolheader.ImmutableThreshold(24 * time.Hour)); got != tt.want {
+				t.Errorf("Header.IsEffectivelyImmutable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package cachecontrolheader
+
+import (
+	"strings"
+	"time"
+)
+
+// WithDefaults returns a copy of the header where every directive not set in h
+// is taken from defaults. Directives set in h are preserved as they are.
+// A boolean directive is regarded as unset when it is false.
+func (h *Header) WithDefaults(defaults *Header) *Header {
+	c := h.clone()
+	for _, name := range directiveNames {
+		if b := c.flag(name); b != nil {
+			*b = *b || *defaults.flag(name)
+		} else if d := c.duration(name); *d == nil {
+			*d = cloneDuration(*defaults.duration(name))
+		}
+	}
+	if len(c.NoCacheFields) == 0 {
+		c.NoCacheFields = append([]string(nil), defaults.NoCacheFields...)
+	}
+	if len(c.PrivateFields) == 0 {
+		c.PrivateFields = append([]string(nil), defaults.PrivateFields...)
+	}
+	for _, e := range defaults.extensions {
+		if !c.hasExtension(e.name) {
+			c.extensions = append(c.extensions, e)
+		}
+	}
+	return c
+}
+
+// Intersect returns a header with only the directives set identically in both h and other.
+// Durations must be exactly equal, and field-name lists must list the same fields in the same order.
+// Extensions must have the same name, compared case-insensitively, and the same value.
+func (h *Header) Intersect(other *Header) *Header {
+	c := &Header{}
+	for _, name := range directiveNames {
+		if b := c.flag(name); b != nil {
+			*b = *h.flag(name) && *other.flag(name)
+		} else if d := c.duration(name); d != nil {
+			hd, od := *h.duration(name), *other.duration(name)
+			if hd != nil && od != nil && *hd == *od {
+				*d = cloneDuration(hd)
+			}
+		}
+	}
+	if equalStrings(h.NoCacheFields, other.NoCacheFields) {
+		c.NoCacheFields = append([]string(nil), h.NoCacheFields...)
+	}
+	if equalStrings(h.PrivateFields, other.PrivateFields) {
+		c.PrivateFields = append([]string(nil), h.PrivateFields...)
+	}
+	for _, e := range h.extensions {
+		for _, o := range other.extensions {
+			if strings.EqualFold(e.name, o.name) && e.value == o.value && e.hasValue == o.hasValue {
+				c.extensions = append(c.extensions, e)
+				break
+			}
+		}
+	}
+	return c
+}
+
+// CapMaxAge returns a copy of the header where max-age and s-maxage are reduced to max
+// if they exceed it. Smaller values are left alone.
+// If neither is set, the copy is returned unchanged; no max-age is added,
+// since that would make a response cacheable that the origin left to heuristics.
+func (h *Header) CapMaxAge(max time.Duration) *Header {
+	c := h.clone()
+	for _, d := range []*time.Duration{c.MaxAge, c.SMaxAge} {
+		if d != nil && *d > max {
+			*d = max
+		}
+	}
+	return c
+}
+
+// ExtendMaxAge returns a copy of the header where max-age is increased by by,
+// e.g. to implement sliding expiration on access.
+// If max-age is not set, it is set to by.
+// A negative by shortens max-age, which is clamped at zero.
+func (h *Header) ExtendMaxAge(by time.Duration) *Header {
+	c := h.clone()
+	var d time.Duration
+	if c.MaxAge != nil {
+		d = *c.MaxAge
+	}
+	d += by
+	if d < 0 {
+		d = 0
+	}
+	c.SetMaxAge(d)
+	return c
+}
+
+// EffectivePolicy combines the Cache-Control headers of a request and its response
+// into the policy a cache applies, shared telling whether the cache is shared.
+// The result starts from a copy of resp, and then:
+//
+//   - for a shared cache, s-maxage replaces max-age (RFC 9111 Section 5.2.2.10),
+//     and unqualified private sets no-store, since a shared cache must not store the response;
+//     for a private cache, s-maxage is dropped;
+//   - the freshness requirements of req are applied by [Header.ConstrainBy];
+//     in addition, max-stale is dropped if proxy-revalidate forbids a shared cache to serve stale;
+//   - no-store and only-if-cached of req are set.
+//
+// A nil req is regarded as empty.
+func EffectivePolicy(req, resp *Header, shared bool) *Header {
+	if req == nil {
+		req = &Header{}
+	}
+	c := resp.clone()
+	if shared {
+		if c.SMaxAge != nil {
+			c.MaxAge = c.SMaxAge
+		}
+		if c.Private {
+			c.NoStore = true
+		}
+	}
+	c.SMaxAge = nil
+
+	c = c.ConstrainBy(req)
+	if shared && c.ProxyRevalidate {
+		c.MaxStale = nil
+	}
+	c.NoStore = c.NoStore || req.NoStore
+	c.OnlyIfCached = req.OnlyIfCached
+	return c
+}
+
+// ConstrainBy returns a copy of the response header with the freshness requirements of the request header req applied,
+// i.e. the policy for serving the stored response to that request:
+//
+//   - max-age and s-maxage become the maximum age at which the response may be served without revalidation:
+//     they are capped at max-age of req (RFC 9111 Section 5.2.1.1), and reduced by min-fresh of req down to zero,
+//     since the response must stay fresh for min-fresh longer (RFC 9111 Section 5.2.1.3);
+//     a response without them is left without, since max-age of req does not make a response fresh;
+//   - no-cache of req is set (RFC 9111 Section 5.2.1.4);
+//   - max-stale of req is kept unless must-revalidate forbids serving stale (RFC 9111 Section 5.2.2.2).
+//
+// min-fresh is cleared, having been applied. A nil req is regarded as empty.
+func (h *Header) ConstrainBy(req *Header) *Header {
+	if req == nil {
+		req = &Header{}
+	}
+	c := h.clone()
+	for _, d := range []**time.Duration{&c.MaxAge, &c.SMaxAge} {
+		if *d == nil {
+			continue
+		}
+		v := **d
+		if req.MaxAge != nil && *req.MaxAge < v {
+			v = *req.MaxAge
+		}
+		if req.MinFresh != nil {
+			v -= *req.MinFresh
+			if v < 0 {
+				v = 0
+			}
+		}
+		*d = &v
+	}
+	c.MinFresh = nil
+	c.NoCache = c.NoCache || req.NoCache
+	c.MaxStale = nil
+	if !c.MustRevalidate {
+		c.MaxStale = cloneDuration(req.MaxStale)
+	}
+	return c
+}
+
+// Simplify returns a copy of the header without the directives made redundant by dominant ones:
+//
+//   - no-store dominates max-age, s-maxage, public, private, immutable, no-cache,
+//     must-revalidate, proxy-revalidate, stale-while-revalidate and stale-if-error,
+//     which only matter for a stored response;
+//   - unqualified no-cache dominates no-cache with field names, and likewise for private.
+//
+// Other directives, e.g. no-transform, must-understand and extensions, are kept.
+func (h *Header) Simplify() *Header {
+	c := h.clone()
+	if c.NoStore {
+		c.MaxAge, c.SMaxAge, c.StaleWhileRevalidate, c.StaleIfError = nil, nil, nil, nil
+		c.Public, c.Private, c.Immutable, c.NoCache = false, false, false, false
+		c.MustRevalidate, c.ProxyRevalidate = false, false
+		c.NoCacheFields, c.PrivateFields = nil, nil
+	}
+	if c.NoCache {
+		c.NoCacheFields = nil
+	}
+	if c.Private {
+		c.PrivateFields = nil
+	}
+	order := c.order[:0]
+	for _, name := range c.order {
+		if c.has(name) || c.hasExtension(name) {
+			order = append(order, name)
+		}
+	}
+	c.order = order
+	return c
+}
+
+// Patch returns the changes turning base into target, to be applied by [Header.ApplyPatch]:
+// set holds the directives to add or replace, and unset the lowercased names of the directives to remove.
+// A directive is replaced as a whole, including its field names. Likewise, the extensions with a name,
+// compared case-insensitively, are replaced as a whole if any of them differs, even only in the case of the name.
+// Directives equal in both are in neither.
+func Patch(base, target *Header) (set *Header, unset []string) {
+	set = &Header{}
+	for _, name := range directiveNames {
+		if sameDirective(base, target, name) {
+			continue
+		}
+		if target.has(name) {
+			set.copyDirective(target, name)
+		} else {
+			unset = append(unset, name)
+		}
+	}
+	for _, name := range extensionNames(target) {
+		if !equalExtensions(base.extensionsNamed(name), target.extensionsNamed(name)) {
+			set.extensions = append(set.extensions, target.extensionsNamed(name)...)
+		}
+	}
+	for _, name := range extensionNames(base) {
+		if !target.hasExtension(name) {
+			unset = append(unset, name)
+		}
+	}
+	return set, unset
+}
+
+// ApplyPatch returns a copy of the header with the changes returned by [Patch] applied:
+// the directives named in unset are removed, and then the directives in set replace the ones in the header.
+// Applying Patch(base, target) to base yields a header equal to target, except for the directive order.
+func (h *Header) ApplyPatch(set *Header, unset []string) *Header {
+	c := h.clone()
+	for _, name := range unset {
+		if contains(directiveNames, name) {
+			c.copyDirective(&Header{}, name)
+		} else {
+			c.RemoveExtension(name)
+		}
+	}
+	for _, name := range directiveNames {
+		if set.has(name) {
+			c.copyDirective(set, name)
+		}
+	}
+	for _, name := range extensionNames(set) {
+		c.RemoveExtension(name)
+	}
+	c.extensions = append(c.extensions, set.extensions...)
+	order := c.order[:0]
+	for _, name := range c.order {
+		if c.has(name) || c.hasExtension(name) {
+			order = append(order, name)
+		}
+	}
+	c.order = order
+	return c
+}
+
+// sameDirective reports whether the known directive named name is in the same state in a and b.
+func sameDirective(a, b *Header, name string) bool {
+	if f := a.flag(name); f != nil && *f != *b.flag(name) {
+		return false
+	}
+	if d := a.duration(name); d != nil {
+		ad, bd := *d, *b.duration(name)
+		if (ad == nil) != (bd == nil) || ad != nil && *ad != *bd {
+			return false
+		}
+	}
+	if fs := a.fields(name); fs != nil && !equalStrings(*fs, *b.fields(name)) {
+		return false
+	}
+	return true
+}
+
+// copyDirective sets the known directive named name, including its field names, to its state in src.
+func (h *Header) copyDirective(src *Header, name string) {
+	if f := h.flag(name); f != nil {
+		*f = *src.flag(name)
+	}
+	if d := h.duration(name); d != nil {
+		*d = cloneDuration(*src.duration(name))
+	}
+	if fs := h.fields(name); fs != nil {
+		*fs = append([]string(nil), *src.fields(name)...)
+	}
+}
+
+// extensionNames returns the lowercased names of the extensions of the header without duplicates.
+func extensionNames(h *Header) []string {
+	var names []string
+	for _, e := range h.extensions {
+		if name := strings.ToLower(e.name); !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// extensionsNamed returns the extensions named name, compared case-insensitively.
+func (h *Header) extensionsNamed(name string) []extension {
+	var es []extension
+	for _, e := range h.extensions {
+		if strings.EqualFold(e.name, name) {
+			es = append(es, e)
+		}
+	}
+	return es
+}
+
+func equalExtensions(a, b []extension) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// clone returns a deep copy of the header.
+func (h *Header) clone() *Header {
+	c := *h
+	for _, name := range directiveNames {
+		if d := c.duration(name); d != nil {
+			*d = cloneDuration(*d)
+		}
+	}
+	c.NoCacheFields = append([]string(nil), h.NoCacheFields...)
+	c.PrivateFields = append([]string(nil), h.PrivateFields...)
+	c.extensions = append([]extension(nil), h.extensions...)
+	c.order = append([]string(nil), h.order...)
+	return &c
+}
+
+// hasExtension reports whether the extension named name is present.
+// The name is compared case-insensitively.
+func (h *Header) hasExtension(name string) bool {
+	for _, e := range h.extensions {
+		if strings.EqualFold(e.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneDuration(d *time.Duration) *time.Duration {
+	if d == nil {
+		return nil
+	}
+	v := *d
+	return &v
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,433 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestHeader_WithDefaults(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name     string
+		header   *cachecontrolheader.Header
+		defaults *cachecontrolheader.Header
+		want     *cachecontrolheader.Header
+	}{
+		{
+			name:   "defaults fill the gaps",
+			header: &cachecontrolheader.Header{Private: true},
+			defaults: &cachecontrolheader.Header{
+				MaxAge:         durationPtr(60 * time.Second),
+				MustRevalidate: true,
+			},
+			want: &cachecontrolheader.Header{
+				MaxAge:         durationPtr(60 * time.Second),
+				MustRevalidate: true,
+				Private:        true,
+			},
+		},
+		{
+			name: "origin directives are preserved",
+			header: &cachecontrolheader.Header{
+				MaxAge: durationPtr(0),
+			},
+			defaults: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(3600 * time.Second),
+				SMaxAge: durationPtr(600 * time.Second),
+			},
+			want: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(0),
+				SMaxAge: durationPtr(600 * time.Second),
+			},
+		},
+		{
+			name:     "empty defaults",
+			header:   &cachecontrolheader.Header{NoStore: true},
+			defaults: &cachecontrolheader.Header{},
+			want:     &cachecontrolheader.Header{NoStore: true},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.header.WithDefaults(tt.defaults)
+			if diff := cmp.Diff(tt.want, got, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeader_WithDefaults_doesNotModifyInputs(t *testing.T) {
+	t.Parallel()
+	h := &cachecontrolheader.Header{Private: true}
+	defaults := &cachecontrolheader.Header{MaxAge: durationPtr(60 * time.Second)}
+	got := h.WithDefaults(defaults)
+	*got.MaxAge = time.Second
+	if h.MaxAge != nil {
+		t.Errorf("header was modified: %v", h)
+	}
+	if *defaults.MaxAge != 60*time.Second {
+		t.Errorf("defaults was modified: %v", defaults)
+	}
+}
+
+func TestHeader_WithDefaults_extensions(t *testing.T) {
+	t.Parallel()
+	h, err := cachecontrolheader.ParseStrict("max-age=60, x-a=1", cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults, err := cachecontrolheader.ParseStrict("x-a=2, x-b", cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := h.WithDefaults(defaults).String(), "max-age=60, x-a=1, x-b"; got != want {
+		t.Errorf("Header.String() = %q, want %q", got, want)
+	}
+}
+
+func TestHeader_Intersect(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name  string
+		h     string
+		other string
+		want  string
+	}{
+		{
+			name:  "partially overlapping",
+			h:     "max-age=60, must-revalidate, public, s-maxage=30",
+			other: "max-age=60, must-revalidate, private, s-maxage=10",
+			want:  "max-age=60, must-revalidate",
+		},
+		{
+			name:  "durations must match exactly",
+			h:     "max-age=60",
+			other: "max-age=61",
+			want:  "",
+		},
+		{
+			name:  "zero durations match",
+			h:     "max-age=0, no-cache",
+			other: "max-age=0",
+			want:  "max-age=0",
+		},
+		{
+			name:  "field-name lists",
+			h:     `private="Set-Cookie", no-cache="A, B"`,
+			other: `private="Set-Cookie", no-cache="A"`,
+			want:  `private="Set-Cookie"`,
+		},
+		{
+			name:  "extensions",
+			h:     "x-a=1, x-b=2, x-c",
+			other: "X-A=1, x-b=3, x-c",
+			want:  "x-a=1, x-c",
+		},
+		{
+			name:  "disjoint",
+			h:     "no-store",
+			other: "public",
+			want:  "",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.h, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			other, err := cachecontrolheader.ParseStrict(tt.other, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := h.Intersect(other).String(); got != tt.want {
+				t.Errorf("Header.Intersect().String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_CapMaxAge(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   string
+	}{
+		{header: "max-age=3600, s-maxage=7200", want: "max-age=600, s-maxage=600"},
+		{header: "max-age=60, s-maxage=7200", want: "max-age=60, s-maxage=600"},
+		{header: "max-age=600", want: "max-age=600"},
+		{header: "public", want: "public"},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h := cachecontrolheader.Parse(tt.header)
+			before := h.String()
+			if got := h.CapMaxAge(10 * time.Minute).String(); got != tt.want {
+				t.Errorf("Header.CapMaxAge().String() = %q, want %q", got, tt.want)
+			}
+			if got := h.String(); got != before {
+				t.Errorf("header was modified: %q, want %q", got, before)
+			}
+		})
+	}
+}
+
+func TestHeader_ExtendMaxAge(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		by     time.Duration
+		want   string
+	}{
+		{header: "max-age=60, public", by: 10 * time.Minute, want: "max-age=660, public"},
+		{header: "max-age=0", by: 10 * time.Minute, want: "max-age=600"},
+		{header: "public", by: 10 * time.Minute, want: "max-age=600, public"},
+		{header: "", by: 10 * time.Minute, want: "max-age=600"},
+		{header: "max-age=60", by: -20 * time.Second, want: "max-age=40"},
+		{header: "max-age=10", by: -20 * time.Second, want: "max-age=0"},
+		{header: "public", by: -20 * time.Second, want: "max-age=0, public"},
+	} {
+		tt := tt
+		t.Run(tt.header+"/"+tt.by.String(), func(t *testing.T) {
+			t.Parallel()
+			h := cachecontrolheader.Parse(tt.header)
+			before := h.String()
+			if got := h.ExtendMaxAge(tt.by).String(); got != tt.want {
+				t.Errorf("Header.ExtendMaxAge().String() = %q, want %q", got, tt.want)
+			}
+			if got := h.String(); got != before {
+				t.Errorf("header was modified: %q, want %q", got, before)
+			}
+		})
+	}
+}
+
+func TestEffectivePolicy(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name   string
+		req    string
+		resp   string
+		shared bool
+		want   string
+	}{
+		{name: "response only", req: "", resp: "max-age=3600, public", want: "max-age=3600, public"},
+		{name: "request no-store", req: "no-store", resp: "max-age=3600", want: "max-age=3600, no-store"},
+		{name: "request no-cache", req: "no-cache", resp: "max-age=3600", want: "max-age=3600, no-cache"},
+		{name: "request max-age caps", req: "max-age=60", resp: "max-age=3600", want: "max-age=60"},
+		{name: "request max-age larger", req: "max-age=7200", resp: "max-age=3600", want: "max-age=3600"},
+		{name: "request max-age without lifetime", req: "max-age=60", resp: "public", want: "public"},
+		{name: "request min-fresh", req: "min-fresh=600", resp: "max-age=3600", want: "max-age=3000"},
+		{name: "request min-fresh exceeding", req: "min-fresh=7200", resp: "max-age=3600", want: "max-age=0"},
+		{name: "shared s-maxage", req: "", resp: "max-age=60, s-maxage=600", shared: true, want: "max-age=600"},
+		{name: "private s-maxage", req: "", resp: "max-age=60, s-maxage=600", want: "max-age=60"},
+		{name: "shared private", req: "", resp: "private, max-age=60", shared: true, want: "max-age=60, no-store, private"},
+		{name: "max-stale", req: "max-stale=30, only-if-cached", resp: "max-age=60", want: "max-age=60, max-stale=30, only-if-cached"},
+		{name: "max-stale with must-revalidate", req: "max-stale=30", resp: "max-age=60, must-revalidate", want: "max-age=60, must-revalidate"},
+		{name: "max-stale with proxy-revalidate in shared cache", req: "max-stale=30", resp: "max-age=60, proxy-revalidate", shared: true, want: "max-age=60, proxy-revalidate"},
+		{name: "max-stale with proxy-revalidate in private cache", req: "max-stale=30", resp: "max-age=60, proxy-revalidate", want: "max-age=60, max-stale=30, proxy-revalidate"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req, resp := cachecontrolheader.Parse(tt.req), cachecontrolheader.Parse(tt.resp)
+			before := resp.String()
+			if got := cachecontrolheader.EffectivePolicy(req, resp, tt.shared).String(); got != tt.want {
+				t.Errorf("EffectivePolicy(%q, %q, %v).String() = %q, want %q", tt.req, tt.resp, tt.shared, got, tt.want)
+			}
+			if got := resp.String(); got != before {
+				t.Errorf("response header was modified: %q, want %q", got, before)
+			}
+		})
+	}
+	if got := cachecontrolheader.EffectivePolicy(nil, cachecontrolheader.Parse("max-age=60"), false).String(); got != "max-age=60" {
+		t.Errorf("EffectivePolicy(nil, ...).String() = %q, want %q", got, "max-age=60")
+	}
+}
+
+func TestHeader_Simplify(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header    string
+		want      string
+		wantOrder []string
+	}{
+		{
+			header:    "no-store, max-age=60, public",
+			want:      "no-store",
+			wantOrder: []string{"no-store"},
+		},
+		{
+			header:    `immutable, no-cache="Set-Cookie", private, s-maxage=60, must-revalidate, proxy-revalidate, no-store`,
+			want:      "no-store",
+			wantOrder: []string{"no-store"},
+		},
+		{
+			header:    "must-understand, no-store, no-transform, x-ext=1",
+			want:      "no-store, no-transform, must-understand, x-ext=1",
+			wantOrder: []string{"must-understand", "no-store", "no-transform", "x-ext"},
+		},
+		{
+			header:    `no-cache, no-cache="Set-Cookie", private="X-A", private, max-age=60`,
+			want:      "max-age=60, no-cache, private",
+			wantOrder: []string{"no-cache", "private", "max-age"},
+		},
+		{
+			header:    "max-age=60, public",
+			want:      "max-age=60, public",
+			wantOrder: []string{"max-age", "public"},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := h.String()
+			got := h.Simplify()
+			if s := got.String(); s != tt.want {
+				t.Errorf("Header.Simplify().String() = %q, want %q", s, tt.want)
+			}
+			if diff := cmp.Diff(tt.wantOrder, got.DirectiveOrder()); diff != "" {
+				t.Errorf("Header.Simplify().DirectiveOrder() mismatch (-want +got):\n%s", diff)
+			}
+			if s := h.String(); s != before {
+				t.Errorf("header was modified: %q, want %q", s, before)
+			}
+		})
+	}
+}
+
+func TestHeader_ConstrainBy(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		req  string
+		resp string
+		want string
+	}{
+		{req: "max-age=10", resp: "max-age=60", want: "max-age=10"},
+		{req: "max-age=600", resp: "max-age=60", want: "max-age=60"},
+		{req: "max-age=10", resp: "max-age=60, s-maxage=5", want: "max-age=10, s-maxage=5"},
+		{req: "max-age=10", resp: "public", want: "public"},
+		{req: "max-age=60", resp: "no-store", want: "no-store"},
+		{req: "min-fresh=20", resp: "max-age=60, s-maxage=120", want: "max-age=40, s-maxage=100"},
+		{req: "max-age=30, min-fresh=20", resp: "max-age=60", want: "max-age=10"},
+		{req: "min-fresh=90", resp: "max-age=60", want: "max-age=0"},
+		{req: "min-fresh=90", resp: "public", want: "public"},
+		{req: "no-cache", resp: "max-age=60", want: "max-age=60, no-cache"},
+		{req: "max-stale=30", resp: "max-age=60", want: "max-age=60, max-stale=30"},
+		{req: "max-stale=30", resp: "max-age=60, must-revalidate", want: "max-age=60, must-revalidate"},
+		{req: "", resp: "max-age=60, private", want: "max-age=60, private"},
+	} {
+		tt := tt
+		t.Run(tt.req+"/"+tt.resp, func(t *testing.T) {
+			t.Parallel()
+			resp := cachecontrolheader.Parse(tt.resp)
+			before := resp.String()
+			if got := resp.ConstrainBy(cachecontrolheader.Parse(tt.req)).String(); got != tt.want {
+				t.Errorf("Header.ConstrainBy(%q).String() = %q, want %q", tt.req, got, tt.want)
+			}
+			if got := resp.String(); got != before {
+				t.Errorf("response header was modified: %q, want %q", got, before)
+			}
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name      string
+		base      string
+		target    string
+		wantSet   string
+		wantUnset []string
+	}{
+		{
+			name:    "identical",
+			base:    "max-age=60, public",
+			target:  "public, max-age=60",
+			wantSet: "",
+		},
+		{
+			name:      "changed, added and removed",
+			base:      "max-age=60, public, must-revalidate",
+			target:    "max-age=120, public, immutable",
+			wantSet:   "max-age=120, immutable",
+			wantUnset: []string{"must-revalidate"},
+		},
+		{
+			name:      "from empty and to empty",
+			base:      "no-store",
+			target:    "",
+			wantUnset: []string{"no-store"},
+		},
+		{
+			name:    "field names replace plain directive",
+			base:    "no-cache, private",
+			target:  `no-cache="Set-Cookie", private`,
+			wantSet: `no-cache="Set-Cookie"`,
+		},
+		{
+			name:      "field names removed",
+			base:      `private="Set-Cookie, Authorization"`,
+			target:    "max-age=0",
+			wantSet:   "max-age=0",
+			wantUnset: []string{"private"},
+		},
+		{
+			name:      "extensions",
+			base:      "x-a=1, x-b=2, x-c",
+			target:    "x-a=1, x-b=3, x-d",
+			wantSet:   "x-b=3, x-d",
+			wantUnset: []string{"x-c"},
+		},
+		{
+			name:    "case of extension names",
+			base:    "x-a=1",
+			target:  "X-A=1",
+			wantSet: "x-a=1",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			base, err := cachecontrolheader.ParseStrict(tt.base, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			target, err := cachecontrolheader.ParseStrict(tt.target, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := base.String()
+			set, unset := cachecontrolheader.Patch(base, target)
+			if got := set.String(); got != tt.wantSet {
+				t.Errorf("set.String() = %q, want %q", got, tt.wantSet)
+			}
+			if diff := cmp.Diff(tt.wantUnset, unset); diff != "" {
+				t.Errorf("unset mismatch (-want +got):\n%s", diff)
+			}
+
+			got := base.ApplyPatch(set, unset)
+			if diff := cmp.Diff(target, got, ignoreUnexported); diff != "" {
+				t.Errorf("Header.ApplyPatch() mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(target.Extensions(), got.Extensions()); diff != "" {
+				t.Errorf("Header.ApplyPatch().Extensions() mismatch (-want +got):\n%s", diff)
+			}
+			if s := base.String(); s != before {
+				t.Errorf("base header was modified: %q, want %q", s, before)
+			}
+		})
+	}
+}
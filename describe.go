@@ -0,0 +1,159 @@
+package cachecontrolheader
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Describe returns a human-readable explanation of the header, one line per directive,
+// e.g. "max-age=3600: fresh for 1h0m0s".
+// It is meant for troubleshooting, and its format may change; use [Header.String] for the header value.
+func (h *Header) Describe() string {
+	var b strings.Builder
+	line := func(directive, format string, args ...interface{}) {
+		fmt.Fprintf(&b, "%s: %s\n", directive, fmt.Sprintf(format, args...))
+	}
+	duration := func(name string, d *time.Duration, format string) {
+		if d != nil {
+			line(fmt.Sprintf("%s=%d", name, int(d.Seconds())), format, *d)
+		}
+	}
+	flag := func(name string, set bool, explanation string) {
+		if set {
+			line(name, "%s", explanation)
+		}
+	}
+	fields := func(name string, fs []string, format string) {
+		if len(fs) > 0 {
+			line(name+"="+quoteString(strings.Join(fs, ", ")), format, strings.Join(fs, ", "))
+		}
+	}
+
+	duration(dMaxAge, h.MaxAge, "fresh for %s")
+	duration(dMaxStale, h.MaxStale, "a response stale by up to %s is acceptable")
+	duration(dMinFresh, h.MinFresh, "a response must stay fresh for at least %s more")
+	flag(dNoCache, h.NoCache, "must be revalidated with the origin before each use")
+	fields(dNoCache, h.NoCacheFields, "the fields %s must not be sent without revalidation")
+	flag(dNoStore, h.NoStore, "must not be stored by any cache")
+	flag(dNoTransform, h.NoTransform, "intermediaries must not transform the content")
+	flag(dOnlyIfCached, h.OnlyIfCached, "only a stored response is acceptable, without contacting the origin")
+	flag(dMustRevalidate, h.MustRevalidate, "must not be used stale without revalidation")
+	flag(dMustUnderstand, h.MustUnderstand, "may be stored only by a cache understanding the status code")
+	flag(dPrivate, h.Private, "must not be stored by a shared cache")
+	fields(dPrivate, h.PrivateFields, "the fields %s must not be stored by a shared cache")
+	flag(dProxyRevalidate, h.ProxyRevalidate, "must not be used stale by a shared cache without revalidation")
+	flag(dPublic, h.Public, "may be stored by any cache, even if it is normally not cacheable")
+	duration(dSMaxAge, h.SMaxAge, "fresh for %s in a shared cache")
+	flag(dImmutable, h.Immutable, "will not change while fresh, so it need not be revalidated")
+	duration(dStaleWhileRevalidate, h.StaleWhileRevalidate, "may be served stale by up to %s while revalidated in the background")
+	duration(dStaleIfError, h.StaleIfError, "may be served stale by up to %s when revalidation fails with an error")
+	for _, e := range h.extensions {
+		line(e.format(false), "extension directive unknown to this package")
+	}
+	return b.String()
+}
+
+// DirectiveDoc is the documentation of a known directive returned by [Header.Explain].
+type DirectiveDoc struct {
+	Name    string // name of the directive, e.g. "max-age"
+	Section string // reference to the definition, e.g. "RFC 9111 Section 5.2.2.1"
+	Summary string // one-sentence summary of the definition
+}
+
+// Explain returns the documentation of each known directive set in the header, in the order of the [Header] fields,
+// with a reference to the section of RFC 9111, or RFC 8246 for immutable and RFC 5861 for the stale-* ones, that defines it.
+// A directive defined for both requests and responses refers to both sections.
+// Extensions are not included, since this package knows no definition of them.
+func (h *Header) Explain() []DirectiveDoc {
+	var docs []DirectiveDoc
+	for _, name := range directiveNames {
+		if h.has(name) {
+			docs = append(docs, directiveDocs[name])
+		}
+	}
+	return docs
+}
+
+var directiveDocs = map[string]DirectiveDoc{
+	dMaxAge: {
+		Name:    dMaxAge,
+		Section: "RFC 9111 Sections 5.2.1.1 and 5.2.2.1",
+		Summary: "In a request, the client prefers a response no older than the value; in a response, the response is fresh until it is that old.",
+	},
+	dMaxStale: {
+		Name:    dMaxStale,
+		Section: "RFC 9111 Section 5.2.1.2",
+		Summary: "The client accepts a response stale by up to the value.",
+	},
+	dMinFresh: {
+		Name:    dMinFresh,
+		Section: "RFC 9111 Section 5.2.1.3",
+		Summary: "The client prefers a response that stays fresh for at least the value longer.",
+	},
+	dNoCache: {
+		Name:    dNoCache,
+		Section: "RFC 9111 Sections 5.2.1.4 and 5.2.2.4",
+		Summary: "A stored response must not be used without successful revalidation with the origin, or only the listed fields when qualified.",
+	},
+	dNoStore: {
+		Name:    dNoStore,
+		Section: "RFC 9111 Sections 5.2.1.5 and 5.2.2.5",
+		Summary: "A cache must not store any part of the request or the response.",
+	},
+	dNoTransform: {
+		Name:    dNoTransform,
+		Section: "RFC 9111 Sections 5.2.1.6 and 5.2.2.6",
+		Summary: "An intermediary must not transform the content.",
+	},
+	dOnlyIfCached: {
+		Name:    dOnlyIfCached,
+		Section: "RFC 9111 Section 5.2.1.7",
+		Summary: "The client wants only a stored response, or a 504 (Gateway Timeout) response otherwise.",
+	},
+	dMustRevalidate: {
+		Name:    dMustRevalidate,
+		Section: "RFC 9111 Section 5.2.2.2",
+		Summary: "Once stale, the response must not be used without successful revalidation with the origin.",
+	},
+	dMustUnderstand: {
+		Name:    dMustUnderstand,
+		Section: "RFC 9111 Section 5.2.2.3",
+		Summary: "A cache may store the response only if it understands the requirements for caching its status code.",
+	},
+	dPrivate: {
+		Name:    dPrivate,
+		Section: "RFC 9111 Section 5.2.2.7",
+		Summary: "A shared cache must not store the response, or only the listed fields when qualified.",
+	},
+	dProxyRevalidate: {
+		Name:    dProxyRevalidate,
+		Section: "RFC 9111 Section 5.2.2.8",
+		Summary: "Like must-revalidate, but only for shared caches.",
+	},
+	dPublic: {
+		Name:    dPublic,
+		Section: "RFC 9111 Section 5.2.2.9",
+		Summary: "A cache may store the response even if it would otherwise be prohibited, e.g. for an authenticated request.",
+	},
+	dSMaxAge: {
+		Name:    dSMaxAge,
+		Section: "RFC 9111 Section 5.2.2.10",
+		Summary: "In a shared cache, the response is fresh until it is the value old, overriding max-age.",
+	},
+	dImmutable: {
+		Name:    dImmutable,
+		Section: "RFC 8246 Section 2",
+		Summary: "The response will not be updated while it is fresh, so it need not be revalidated.",
+	},
+	dStaleWhileRevalidate: {
+		Name:    dStaleWhileRevalidate,
+		Section: "RFC 5861 Section 3",
+		Summary: "A cache may serve the response stale by up to the value while it revalidates it in the background.",
+	},
+	dStaleIfError: {
+		Name:    dStaleIfError,
+		Section: "RFC 5861 Section 4",
+		Summary: "A cache may serve the response stale by up to the value when revalidation fails with an error.",
+	},
+}
@@ -0,0 +1,65 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestHeader_Describe(t *testing.T) {
+	t.Parallel()
+	h, err := cachecontrolheader.ParseStrict(`max-age=3600, private="Set-Cookie", must-revalidate, s-maxage=60, x-vendor`, cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `max-age=3600: fresh for 1h0m0s
+must-revalidate: must not be used stale without revalidation
+private="Set-Cookie": the fields Set-Cookie must not be stored by a shared cache
+s-maxage=60: fresh for 1m0s in a shared cache
+x-vendor: extension directive unknown to this package
+`
+	if got := h.Describe(); got != want {
+		t.Errorf("Header.Describe() = %q, want %q", got, want)
+	}
+}
+
+func TestHeader_Describe_empty(t *testing.T) {
+	t.Parallel()
+	if got := (&cachecontrolheader.Header{}).Describe(); got != "" {
+		t.Errorf("Header.Describe() = %q, want %q", got, "")
+	}
+}
+
+func TestHeader_Explain(t *testing.T) {
+	t.Parallel()
+	h, err := cachecontrolheader.ParseStrict(`s-maxage=60, private="Set-Cookie", max-age=3600, immutable, stale-if-error=60, x-vendor`, cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := h.Explain()
+	want := []struct{ name, section string }{
+		{name: "max-age", section: "RFC 9111 Sections 5.2.1.1 and 5.2.2.1"},
+		{name: "private", section: "RFC 9111 Section 5.2.2.7"},
+		{name: "s-maxage", section: "RFC 9111 Section 5.2.2.10"},
+		{name: "immutable", section: "RFC 8246 Section 2"},
+		{name: "stale-if-error", section: "RFC 5861 Section 4"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Header.Explain() = %+v, want %d docs", got, len(want))
+	}
+	for i, w := range want {
+		if got[i].Name != w.name || got[i].Section != w.section {
+			t.Errorf("Header.Explain()[%d] = %s (%s), want %s (%s)", i, got[i].Name, got[i].Section, w.name, w.section)
+		}
+		if got[i].Summary == "" {
+			t.Errorf("Header.Explain()[%d].Summary is empty", i)
+		}
+	}
+}
+
+func TestHeader_Explain_empty(t *testing.T) {
+	t.Parallel()
+	if got := (&cachecontrolheader.Header{}).Explain(); got != nil {
+		t.Errorf("Header.Explain() = %+v, want nil", got)
+	}
+}
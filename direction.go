@@ -0,0 +1,172 @@
+package cachecontrolheader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseResponse parses a Cache-Control header of a response like [ParseStrict].
+// The request-only directives, i.e. max-stale, min-fresh and only-if-cached (RFC 9111 Section 5.2.1),
+// are invalid in a response, so they are treated like unknown directives:
+// it returns an error for them, or ignores them with the [IgnoreUnknownDirectives] option.
+func ParseResponse(header string, opts ...parseOption) (*Header, error) {
+	return parse(header, appendOption(opts, func(o *option) {
+		o.direction = DirectionResponse
+	})...)
+}
+
+// ParseRequest parses a Cache-Control header of a request like [ParseStrict].
+// The response-only directives, e.g. public and s-maxage (RFC 9111 Section 5.2.2),
+// are invalid in a request, so they are treated like unknown directives:
+// it returns an error for them, or ignores them with the [IgnoreUnknownDirectives] option.
+// no-cache in a request takes no field-name list, so `no-cache="Set-Cookie"` is an invalid value:
+// it returns an error for it, or regards it as a plain no-cache with the [IgnoreInvalidValues] option.
+func ParseRequest(header string, opts ...parseOption) (*Header, error) {
+	return parse(header, appendOption(opts, func(o *option) {
+		o.direction = DirectionRequest
+	})...)
+}
+
+// AsRequestString returns a string representation of the header like [Header.String],
+// with only the directives valid in a request: the response-only directives are omitted,
+// and so are the field names of no-cache, which is a plain boolean in a request.
+func (h *Header) AsRequestString() string {
+	return h.StringWith(func(c *stringConfig) {
+		c.direction = DirectionRequest
+	})
+}
+
+// AsResponseString returns a string representation of the header like [Header.String],
+// with only the directives valid in a response: the request-only directives are omitted.
+func (h *Header) AsResponseString() string {
+	return h.StringWith(func(c *stringConfig) {
+		c.direction = DirectionResponse
+	})
+}
+
+// Direction is the kind of HTTP message a header belongs to.
+type Direction int
+
+const (
+	// DirectionAny means either a request or a response, allowing every directive.
+	DirectionAny Direction = iota
+	// DirectionRequest means a request, allowing no response-only directives.
+	DirectionRequest
+	// DirectionResponse means a response, allowing no request-only directives.
+	DirectionResponse
+)
+
+// String returns a string representation of the direction.
+func (d Direction) String() string {
+	switch d {
+	case DirectionAny:
+		return "any"
+	case DirectionRequest:
+		return "request"
+	case DirectionResponse:
+		return "response"
+	default:
+		return fmt.Sprintf("Direction(%d)", int(d))
+	}
+}
+
+// ValidateFor returns an error if the header has directives invalid for the direction d,
+// e.g. on a hand-built header before serialization: the response-only directives for [DirectionRequest],
+// including no-cache with field names, and the request-only directives for [DirectionResponse].
+// The error lists every offending directive, in the order of the [Header] fields.
+func (h *Header) ValidateFor(d Direction) error {
+	var msgs []string
+	for _, name := range directiveNames {
+		if !h.has(name) {
+			continue
+		}
+		if err := d.check(name); err != nil {
+			msgs = append(msgs, err.Error())
+		}
+		if name == dNoCache && d == DirectionRequest && len(h.NoCacheFields) > 0 {
+			msgs = append(msgs, fmt.Sprintf("field names of no-cache in request: %s", strings.Join(h.NoCacheFields, ", ")))
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// check returns an error if the directive named name is not allowed in the direction.
+func (d Direction) check(name string) error {
+	if d == DirectionResponse && contains(requestOnlyDirectives, name) {
+		return fmt.Errorf("request-only directive in response: %s", name)
+	}
+	if d == DirectionRequest && contains(responseOnlyDirectives, name) {
+		return fmt.Errorf("response-only directive in request: %s", name)
+	}
+	return nil
+}
+
+// requestOnlyDirectives lists the directives defined only for requests (RFC 9111 Section 5.2.1).
+var requestOnlyDirectives = []string{
+	dMaxStale,
+	dMinFresh,
+	dOnlyIfCached,
+}
+
+// responseOnlyDirectives lists the directives defined only for responses (RFC 9111 Section 5.2.2, RFC 8246 and RFC 5861).
+// stale-if-error is defined for both requests and responses (RFC 5861 Section 4).
+var responseOnlyDirectives = []string{
+	dMustRevalidate,
+	dMustUnderstand,
+	dPrivate,
+	dProxyRevalidate,
+	dPublic,
+	dSMaxAge,
+	dImmutable,
+	dStaleWhileRevalidate,
+}
+
+// HasRequestOnlyDirectives returns the names of the request-only directives set in the header,
+// i.e. max-stale, min-fresh and only-if-cached.
+// They are meaningless in a response, so their presence suggests a misbehaving origin.
+// An empty result means there are none.
+func (h *Header) HasRequestOnlyDirectives() []string {
+	var ds []string
+	for _, name := range requestOnlyDirectives {
+		if h.has(name) {
+			ds = append(ds, name)
+		}
+	}
+	return ds
+}
+
+// OnlyIfCachedRequested reports whether the request header has only-if-cached,
+// meaning the client wants only a stored response (RFC 9111 Section 5.2.1.7).
+// A cache must then respond with a stored response satisfying the other request directives,
+// or with 504 (Gateway Timeout), without contacting the origin.
+// only-if-cached is request-only: [ParseRequest] accepts it, while [ParseResponse] rejects it.
+func (h *Header) OnlyIfCachedRequested() bool {
+	return h.OnlyIfCached
+}
+
+// has reports whether the known directive named name is set, in any form.
+func (h *Header) has(name string) bool {
+	if fs := h.fields(name); fs != nil && len(*fs) > 0 {
+		return true
+	}
+	if b := h.flag(name); b != nil {
+		return *b
+	}
+	if d := h.duration(name); d != nil {
+		return *d != nil
+	}
+	return false
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,271 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestHeader_HasRequestOnlyDirectives(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   []string
+	}{
+		{
+			header: "max-age=60, public, must-revalidate",
+		},
+		{
+			header: "",
+		},
+		{
+			header: "max-age=60, only-if-cached",
+			want:   []string{"only-if-cached"},
+		},
+		{
+			header: "min-fresh=10, max-stale=0, only-if-cached, private",
+			want:   []string{"max-stale", "min-fresh", "only-if-cached"},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			got := cachecontrolheader.Parse(tt.header).HasRequestOnlyDirectives()
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("HasRequestOnlyDirectives mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			header: "max-age=60, public",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(60 * time.Second),
+				Public: true,
+			},
+		},
+		{
+			header:  "max-age=60, max-stale=10",
+			wantErr: true,
+		},
+		{
+			header:  "max-age=60, min-fresh=10",
+			wantErr: true,
+		},
+		{
+			header:  "max-age=60, Only-If-Cached",
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseResponse(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseResponse_IgnoreUnknownDirectives(t *testing.T) {
+	t.Parallel()
+	h, err := cachecontrolheader.ParseResponse("max-age=60, max-stale=10, min-fresh=10, only-if-cached", cachecontrolheader.IgnoreUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &cachecontrolheader.Header{
+		MaxAge: durationPtr(60 * time.Second),
+	}
+	if diff := cmp.Diff(want, h, ignoreUnexported); diff != "" {
+		t.Errorf("Header mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			header: "no-cache, max-stale=10, only-if-cached",
+			wantHeader: &cachecontrolheader.Header{
+				NoCache:      true,
+				MaxStale:     durationPtr(10 * time.Second),
+				OnlyIfCached: true,
+			},
+		},
+		{
+			header:  `no-cache="Set-Cookie"`,
+			wantErr: true,
+		},
+		{
+			header:  "max-age=60, public",
+			wantErr: true,
+		},
+		{
+			header:  "s-maxage=60",
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseRequest(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseRequest_lenient(t *testing.T) {
+	t.Parallel()
+	h, err := cachecontrolheader.ParseRequest(`no-cache="Set-Cookie", max-age=60, public`, cachecontrolheader.IgnoreInvalidValues(), cachecontrolheader.IgnoreUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &cachecontrolheader.Header{
+		MaxAge:  durationPtr(60 * time.Second),
+		NoCache: true,
+	}
+	if diff := cmp.Diff(want, h, ignoreUnexported); diff != "" {
+		t.Errorf("Header mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHeader_AsRequestString_AsResponseString(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header       string
+		wantRequest  string
+		wantResponse string
+	}{
+		{
+			header:       `max-age=60, max-stale=10, min-fresh=5, no-cache="Set-Cookie", only-if-cached, private="X-A", public, s-maxage=30, immutable`,
+			wantRequest:  "max-age=60, max-stale=10, min-fresh=5, only-if-cached",
+			wantResponse: `max-age=60, no-cache="Set-Cookie", private="X-A", public, s-maxage=30, immutable`,
+		},
+		{
+			header:       "max-age=60, stale-while-revalidate=30, stale-if-error=600",
+			wantRequest:  "max-age=60, stale-if-error=600",
+			wantResponse: "max-age=60, stale-while-revalidate=30, stale-if-error=600",
+		},
+		{
+			header:       "no-cache, no-store, no-transform, x-ext",
+			wantRequest:  "no-cache, no-store, no-transform, x-ext",
+			wantResponse: "no-cache, no-store, no-transform, x-ext",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := h.AsRequestString(); got != tt.wantRequest {
+				t.Errorf("Header.AsRequestString() = %q, want %q", got, tt.wantRequest)
+			}
+			if got := h.AsResponseString(); got != tt.wantResponse {
+				t.Errorf("Header.AsResponseString() = %q, want %q", got, tt.wantResponse)
+			}
+			if _, err := cachecontrolheader.ParseRequest(h.AsRequestString(), cachecontrolheader.CaptureUnknownDirectives()); err != nil {
+				t.Errorf("ParseRequest(Header.AsRequestString()) got error: %v", err)
+			}
+			if _, err := cachecontrolheader.ParseResponse(h.AsResponseString(), cachecontrolheader.CaptureUnknownDirectives()); err != nil {
+				t.Errorf("ParseResponse(Header.AsResponseString()) got error: %v", err)
+			}
+		})
+	}
+}
+
+func TestHeader_OnlyIfCachedRequested(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   bool
+	}{
+		{header: "only-if-cached", want: true},
+		{header: "max-stale=10, only-if-cached", want: true},
+		{header: "max-age=0", want: false},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseRequest(tt.header)
+			if err != nil {
+				t.Fatalf("ParseRequest() got error: %v", err)
+			}
+			if got := h.OnlyIfCachedRequested(); got != tt.want {
+				t.Errorf("Header.OnlyIfCachedRequested() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := cachecontrolheader.ParseResponse("max-age=60, only-if-cached"); err == nil {
+		t.Error("ParseResponse() with only-if-cached got no error")
+	}
+}
+
+func TestHeader_ValidateFor(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header    string
+		direction cachecontrolheader.Direction
+		wantErr   string
+	}{
+		{
+			header:    "max-age=60, max-stale=10, public, only-if-cached, s-maxage=30",
+			direction: cachecontrolheader.DirectionResponse,
+			wantErr:   "request-only directive in response: max-stale; request-only directive in response: only-if-cached",
+		},
+		{
+			header:    `max-age=60, max-stale=10, public, no-cache="Set-Cookie", s-maxage=30`,
+			direction: cachecontrolheader.DirectionRequest,
+			wantErr:   "field names of no-cache in request: Set-Cookie; response-only directive in request: public; response-only directive in request: s-maxage",
+		},
+		{
+			header:    "max-age=60, max-stale=10, public",
+			direction: cachecontrolheader.DirectionAny,
+		},
+		{
+			header:    "max-age=60, no-cache, no-store",
+			direction: cachecontrolheader.DirectionRequest,
+		},
+		{
+			header:    "max-age=60, no-cache, no-store",
+			direction: cachecontrolheader.DirectionResponse,
+		},
+	} {
+		tt := tt
+		t.Run(tt.direction.String()+"/"+tt.header, func(t *testing.T) {
+			t.Parallel()
+			err := cachecontrolheader.Parse(tt.header).ValidateFor(tt.direction)
+			var gotErr string
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("Header.ValidateFor(%v) got error: %q, want: %q", tt.direction, gotErr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+//go:build go1.23
+// +build go1.23
+
+package cachecontrolheader
+
+import (
+	"iter"
+	"time"
+)
+
+// DurationDirectives returns an iterator over the name and the value of each duration directive set in the header,
+// e.g. for metrics on TTLs: max-age, max-stale, min-fresh, s-maxage, stale-while-revalidate and stale-if-error,
+// in the order of the [Header] fields. Boolean directives are not yielded.
+func (h *Header) DurationDirectives() iter.Seq2[string, time.Duration] {
+	return func(yield func(string, time.Duration) bool) {
+		for _, name := range directiveNames {
+			if d := h.duration(name); d != nil && *d != nil {
+				if !yield(name, **d) {
+					return
+				}
+			}
+		}
+	}
+}
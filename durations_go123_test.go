@@ -0,0 +1,58 @@
+//go:build go1.23
+// +build go1.23
+
+package cachecontrolheader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+type durationDirective struct {
+	Name  string
+	Value time.Duration
+}
+
+func TestHeader_DurationDirectives(t *testing.T) {
+	t.Parallel()
+	h := cachecontrolheader.Parse("stale-if-error=600, public, s-maxage=60, max-age=30, no-cache, Stale-While-Revalidate=10, min-fresh=5, x-a=1")
+	var got []durationDirective
+	// Call the iterator directly rather than ranging over it, since the module's go version predates range-over-func.
+	h.DurationDirectives()(func(name string, d time.Duration) bool {
+		got = append(got, durationDirective{Name: name, Value: d})
+		return true
+	})
+	want := []durationDirective{
+		{Name: "max-age", Value: 30 * time.Second},
+		{Name: "min-fresh", Value: 5 * time.Second},
+		{Name: "s-maxage", Value: 60 * time.Second},
+		{Name: "stale-while-revalidate", Value: 10 * time.Second},
+		{Name: "stale-if-error", Value: 600 * time.Second},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Header.DurationDirectives mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHeader_DurationDirectives_break(t *testing.T) {
+	t.Parallel()
+	var got []string
+	cachecontrolheader.Parse("max-age=30, max-stale=10, min-fresh=5").DurationDirectives()(func(name string, _ time.Duration) bool {
+		got = append(got, name)
+		return len(got) < 2
+	})
+	if diff := cmp.Diff([]string{"max-age", "max-stale"}, got); diff != "" {
+		t.Errorf("Header.DurationDirectives mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestHeader_DurationDirectives_empty(t *testing.T) {
+	t.Parallel()
+	cachecontrolheader.Parse("no-store, public").DurationDirectives()(func(name string, _ time.Duration) bool {
+		t.Errorf("Header.DurationDirectives yielded %s", name)
+		return true
+	})
+}
@@ -23,5 +23,5 @@ func Example_parseStrict() {
 	fmt.Println(err)
 	// Output:
 	// unknown directive: ???
-	// failed to parse the value of directive(max-age=invalid): time: invalid duration "invalids"
+	// failed to parse the value of directive(max-age=invalid): invalid delta-seconds "invalid"
 }
@@ -0,0 +1,95 @@
+package cachecontrolheader
+
+import "time"
+
+// RemainingTTL returns how long a response with the header stays fresh:
+// its freshness lifetime minus the time elapsed since responseDate, clamped at zero.
+// The freshness lifetime is s-maxage for a shared cache if present, and max-age otherwise (RFC 9111 Section 4.2.1).
+// The bool reports whether the header specifies a freshness lifetime at all;
+// if it does not, RemainingTTL returns 0 and false.
+//
+// responseDate and now are provided by the caller, e.g. the Date header of the response and time.Now(),
+// so the result is only as accurate as their clocks.
+func (h *Header) RemainingTTL(responseDate, now time.Time, shared bool) (time.Duration, bool) {
+	lifetime, ok := h.freshnessLifetime(shared)
+	if !ok {
+		return 0, false
+	}
+	if remaining := lifetime - now.Sub(responseDate); remaining > 0 {
+		return remaining, true
+	}
+	return 0, true
+}
+
+// freshnessLifetime returns the freshness lifetime given by the header (RFC 9111 Section 4.2.1).
+// It returns false if the header has neither s-maxage (for a shared cache) nor max-age.
+func (h *Header) freshnessLifetime(shared bool) (time.Duration, bool) {
+	if shared && h.SMaxAge != nil {
+		return *h.SMaxAge, true
+	}
+	if h.MaxAge != nil {
+		return *h.MaxAge, true
+	}
+	return 0, false
+}
+
+// CanUseWithoutRevalidation reports whether a stored response with the header can be used
+// without revalidation when its current age is age, shared telling whether the cache is shared.
+// It returns true exactly when all of the following hold:
+//
+//   - no-store is not set, and for a shared cache, unqualified private is not set either,
+//     since the response should not have been stored then;
+//   - [Header.AlwaysRevalidate] is false, i.e. there is no unqualified no-cache, nor max-age=0 with must-revalidate;
+//   - the response is fresh: the freshness lifetime, s-maxage for a shared cache or max-age, is greater than age
+//     (RFC 9111 Section 4.2). Without either of them, it returns false, as heuristic freshness is not considered.
+//
+// must-revalidate and proxy-revalidate only forbid serving a stale response, so they do not matter otherwise.
+// A no-cache qualified with field names does not matter either; see [Header.RevalidationTarget] for the fields.
+func (h *Header) CanUseWithoutRevalidation(age time.Duration, shared bool) bool {
+	if h.NoStore || shared && h.Private || h.AlwaysRevalidate() {
+		return false
+	}
+	lifetime, ok := h.freshnessLifetime(shared)
+	return ok && lifetime > age
+}
+
+// StronglyCacheable reports whether a response with the header can be treated as strongly cacheable,
+// e.g. to choose a strong ETag strategy. It returns true when neither no-store nor no-cache is set
+// and either immutable is set or max-age is greater than threshold.
+// must-revalidate does not matter here since it only applies once the response is stale.
+func (h *Header) StronglyCacheable(threshold time.Duration) bool {
+	if h.NoStore || h.NoCache {
+		return false
+	}
+	return h.Immutable || h.MaxAge != nil && *h.MaxAge > threshold
+}
+
+// CacheFor returns a response header caching the response for ttl:
+// `max-age=<ttl>` for private caches only, or `max-age=<ttl>, public, s-maxage=<ttl>` when shared is true,
+// so that shared caches store it even for authenticated requests.
+// A negative ttl is treated as zero.
+func CacheFor(ttl time.Duration, shared bool) *Header {
+	if ttl < 0 {
+		ttl = 0
+	}
+	h := &Header{}
+	h.SetMaxAge(ttl)
+	if shared {
+		h.Public = true
+		h.SetSMaxAge(ttl)
+	}
+	return h
+}
+
+// MaxStaleServeWindow returns the longest time a response with the header may be served stale for any reason,
+// i.e. the larger of stale-while-revalidate and stale-if-error (RFC 5861), e.g. to size a background-refresh scheduler.
+// It returns 0 if neither is set.
+func (h *Header) MaxStaleServeWindow() time.Duration {
+	var window time.Duration
+	for _, d := range []*time.Duration{h.StaleWhileRevalidate, h.StaleIfError} {
+		if d != nil && *d > window {
+			window = *d
+		}
+	}
+	return window
+}
@@ -0,0 +1,188 @@
+package cachecontrolheader_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestHeader_RemainingTTL(t *testing.T) {
+	t.Parallel()
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name    string
+		header  string
+		elapsed time.Duration
+		shared  bool
+		want    time.Duration
+		wantOK  bool
+	}{
+		{
+			name:    "fresh",
+			header:  "max-age=60",
+			elapsed: 20 * time.Second,
+			want:    40 * time.Second,
+			wantOK:  true,
+		},
+		{
+			name:    "boundary",
+			header:  "max-age=60",
+			elapsed: 60 * time.Second,
+			want:    0,
+			wantOK:  true,
+		},
+		{
+			name:    "expired",
+			header:  "max-age=60",
+			elapsed: 90 * time.Second,
+			want:    0,
+			wantOK:  true,
+		},
+		{
+			name:    "shared cache uses s-maxage",
+			header:  "max-age=60, s-maxage=600",
+			elapsed: 100 * time.Second,
+			shared:  true,
+			want:    500 * time.Second,
+			wantOK:  true,
+		},
+		{
+			name:    "private cache ignores s-maxage",
+			header:  "max-age=60, s-maxage=600",
+			elapsed: 100 * time.Second,
+			want:    0,
+			wantOK:  true,
+		},
+		{
+			name:    "no lifetime",
+			header:  "public",
+			elapsed: 0,
+			want:    0,
+			wantOK:  false,
+		},
+		{
+			name:    "only s-maxage for private cache",
+			header:  "s-maxage=600",
+			elapsed: 0,
+			want:    0,
+			wantOK:  false,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := cachecontrolheader.Parse(tt.header).RemainingTTL(date, date.Add(tt.elapsed), tt.shared)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Header.RemainingTTL() = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHeader_StronglyCacheable(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   bool
+	}{
+		{header: "max-age=3599", want: false},
+		{header: "max-age=3600", want: false},
+		{header: "max-age=3601", want: true},
+		{header: "max-age=3601, must-revalidate", want: true},
+		{header: "max-age=60, immutable", want: true},
+		{header: "immutable", want: true},
+		{header: "max-age=86400, no-cache", want: false},
+		{header: "immutable, no-store", want: false},
+		{header: "s-maxage=86400", want: false},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.Parse(tt.header).StronglyCacheable(time.Hour); got != tt.want {
+				t.Errorf("Header.StronglyCacheable(time.Hour) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_CanUseWithoutRevalidation(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		age    time.Duration
+		shared bool
+		want   bool
+	}{
+		{header: "max-age=60", age: 59 * time.Second, want: true},
+		{header: "max-age=60", age: 60 * time.Second, want: false},
+		{header: "max-age=60, must-revalidate", age: 59 * time.Second, want: true},
+		{header: "max-age=60, must-revalidate", age: 60 * time.Second, want: false},
+		{header: "max-age=0, must-revalidate", age: 0, want: false},
+		{header: "max-age=60, no-cache", age: 0, want: false},
+		{header: `max-age=60, no-cache="Set-Cookie"`, age: 0, want: true},
+		{header: "max-age=60, no-store", age: 0, want: false},
+		{header: "max-age=60, s-maxage=10", age: 30 * time.Second, want: true},
+		{header: "max-age=60, s-maxage=10", age: 30 * time.Second, shared: true, want: false},
+		{header: "max-age=60, s-maxage=10", age: 9 * time.Second, shared: true, want: true},
+		{header: "max-age=60, private", age: 0, want: true},
+		{header: "max-age=60, private", age: 0, shared: true, want: false},
+		{header: "public", age: 0, want: false},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("%s/%v/%v", tt.header, tt.age, tt.shared), func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.Parse(tt.header).CanUseWithoutRevalidation(tt.age, tt.shared); got != tt.want {
+				t.Errorf("Header.CanUseWithoutRevalidation(%v, %v) = %v, want %v", tt.age, tt.shared, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheFor(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		ttl    time.Duration
+		shared bool
+		want   string
+	}{
+		{ttl: time.Hour, want: "max-age=3600"},
+		{ttl: time.Hour, shared: true, want: "max-age=3600, public, s-maxage=3600"},
+		{ttl: 0, want: "max-age=0"},
+		{ttl: -time.Second, shared: true, want: "max-age=0, public, s-maxage=0"},
+	} {
+		tt := tt
+		t.Run(tt.want, func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.CacheFor(tt.ttl, tt.shared).String(); got != tt.want {
+				t.Errorf("CacheFor(%v, %v).String() = %q, want %q", tt.ttl, tt.shared, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_MaxStaleServeWindow(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   time.Duration
+	}{
+		{header: "max-age=60", want: 0},
+		{header: "max-age=60, stale-while-revalidate=30", want: 30 * time.Second},
+		{header: "max-age=60, stale-if-error=86400", want: 86400 * time.Second},
+		{header: "stale-while-revalidate=300, stale-if-error=60", want: 300 * time.Second},
+		{header: "stale-while-revalidate=60, stale-if-error=300", want: 300 * time.Second},
+		{header: "Stale-While-Revalidate=120", want: 120 * time.Second},
+		{header: "stale-while-revalidate=1m, stale-if-error=10", want: 10 * time.Second},
+		{header: "stale-while-revalidate", want: 0},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.Parse(tt.header).MaxStaleServeWindow(); got != tt.want {
+				t.Errorf("Header.MaxStaleServeWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
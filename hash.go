@@ -0,0 +1,13 @@
+package cachecontrolheader
+
+import "hash/fnv"
+
+// Hash returns a 64-bit fingerprint of the directives of the header, e.g. for a cache key.
+// It is the FNV-1a hash of the canonical string representation, i.e. [Header.StringWith] with [SortExtensions],
+// so it does not depend on the order the directives were parsed in, nor on the case of their names.
+// The order of field names within no-cache and private, and extension values, are significant.
+func (h *Header) Hash() uint64 {
+	f := fnv.New64a()
+	f.Write([]byte(h.StringWith(SortExtensions())))
+	return f.Sum64()
+}
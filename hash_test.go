@@ -0,0 +1,42 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestHeader_Hash(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		a, b  string
+		equal bool
+	}{
+		{a: "max-age=60, public", b: "public, max-age=60", equal: true},
+		{a: "Max-Age=60, PUBLIC", b: "max-age=60, public", equal: true},
+		{a: "x-b=2, x-a, no-store", b: "no-store, X-A, x-b=2", equal: true},
+		{a: "max-age=60, max-age=60", b: "max-age=60", equal: true},
+		{a: "", b: " , ", equal: true},
+		{a: "max-age=60", b: "max-age=61", equal: false},
+		{a: "max-age=60", b: "s-maxage=60", equal: false},
+		{a: "no-cache", b: `no-cache="Set-Cookie"`, equal: false},
+		{a: "x-ext=1", b: "x-ext=2", equal: false},
+		{a: "", b: "public", equal: false},
+	} {
+		tt := tt
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			t.Parallel()
+			a, err := cachecontrolheader.ParseStrict(tt.a, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := cachecontrolheader.ParseStrict(tt.b, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := a.Hash() == b.Hash(); got != tt.equal {
+				t.Errorf("Header.Hash() of %q and %q equal = %v, want %v", tt.a, tt.b, got, tt.equal)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package cachecontrolheader
+
+import (
+	"net/http"
+	"strings"
+)
+
+// FromRequest parses the Cache-Control header of r leniently like [Parse], as request directives:
+// the response-only directives are dropped as in [ParseRequest] with [IgnoreUnknownDirectives],
+// and so are unknown directives and invalid values.
+// Multiple Cache-Control field lines are combined into one list.
+func FromRequest(r *http.Request) *Header {
+	h, _ := ParseRequest(strings.Join(r.Header.Values("Cache-Control"), ", "), IgnoreInvalidValues(), IgnoreUnknownDirectives())
+	return h
+}
+
+// FromResponse parses the Cache-Control header of r leniently like [Parse], as response directives:
+// the request-only directives are dropped as in [ParseResponse] with [IgnoreUnknownDirectives],
+// and so are unknown directives and invalid values.
+// Multiple Cache-Control field lines are combined into one list.
+func FromResponse(r *http.Response) *Header {
+	h, _ := ParseResponse(strings.Join(r.Header.Values("Cache-Control"), ", "), IgnoreInvalidValues(), IgnoreUnknownDirectives())
+	return h
+}
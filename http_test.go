@@ -0,0 +1,96 @@
+package cachecontrolheader_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestFromRequest(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name       string
+		values     []string
+		wantHeader *cachecontrolheader.Header
+	}{
+		{
+			name:   "request directives",
+			values: []string{"max-age=0, no-cache", "max-stale=30, only-if-cached"},
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:       durationPtr(0),
+				MaxStale:     durationPtr(30 * time.Second),
+				NoCache:      true,
+				OnlyIfCached: true,
+			},
+		},
+		{
+			name:   "response-only, unknown and invalid directives dropped",
+			values: []string{`public, s-maxage=60, no-cache="Set-Cookie", min-fresh=invalid, unknown, no-store`},
+			wantHeader: &cachecontrolheader.Header{
+				NoCache: true,
+				NoStore: true,
+			},
+		},
+		{
+			name:       "no Cache-Control",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest("GET", "/", nil)
+			for _, v := range tt.values {
+				r.Header.Add("Cache-Control", v)
+			}
+			if diff := cmp.Diff(tt.wantHeader, cachecontrolheader.FromRequest(r), ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFromResponse(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name       string
+		header     http.Header
+		wantHeader *cachecontrolheader.Header
+	}{
+		{
+			name:   "response directives",
+			header: http.Header{"Cache-Control": {`max-age=60, private="Set-Cookie"`, "s-maxage=600, must-revalidate"}},
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:         durationPtr(60 * time.Second),
+				PrivateFields:  []string{"Set-Cookie"},
+				SMaxAge:        durationPtr(600 * time.Second),
+				MustRevalidate: true,
+			},
+		},
+		{
+			name:   "request-only, unknown and invalid directives dropped",
+			header: http.Header{"Cache-Control": {"max-stale=10, only-if-cached, max-age=invalid, unknown, public"}},
+			wantHeader: &cachecontrolheader.Header{
+				Public: true,
+			},
+		},
+		{
+			name:       "no Cache-Control",
+			header:     http.Header{},
+			wantHeader: &cachecontrolheader.Header{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := &http.Response{StatusCode: http.StatusOK, Header: tt.header}
+			if diff := cmp.Diff(tt.wantHeader, cachecontrolheader.FromResponse(r), ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
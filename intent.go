@@ -0,0 +1,79 @@
+package cachecontrolheader
+
+import (
+	"fmt"
+	"time"
+)
+
+// Intent represents a caching policy intent for [ForIntent], e.g. from a declarative config.
+type Intent int
+
+const (
+	// IntentNoStore forbids storing the response: `no-store`.
+	IntentNoStore Intent = iota
+	// IntentNoCache allows storing the response but requires revalidation on every use: `no-cache`.
+	IntentNoCache
+	// IntentImmutableAsset caches a fingerprinted asset forever in any cache:
+	// `max-age=31536000, public, immutable` by default.
+	IntentImmutableAsset
+	// IntentPrivateShort caches a user-specific response briefly in the browser only:
+	// `max-age=60, private` by default.
+	IntentPrivateShort
+	// IntentPublicShort caches a response briefly in any cache: `max-age=60, public` by default.
+	IntentPublicShort
+)
+
+// Default freshness lifetimes used by [ForIntent].
+const (
+	DefaultImmutableAssetTTL = 365 * 24 * time.Hour
+	DefaultShortTTL          = time.Minute
+)
+
+// String returns a string representation of the intent.
+func (i Intent) String() string {
+	switch i {
+	case IntentNoStore:
+		return "no-store"
+	case IntentNoCache:
+		return "no-cache"
+	case IntentImmutableAsset:
+		return "immutable-asset"
+	case IntentPrivateShort:
+		return "private-short"
+	case IntentPublicShort:
+		return "public-short"
+	default:
+		return fmt.Sprintf("Intent(%d)", int(i))
+	}
+}
+
+// ForIntent returns a minimal response header for the intent i, as documented on each [Intent].
+// For the intents with a max-age, ttl overrides the default lifetime, [DefaultImmutableAssetTTL]
+// or [DefaultShortTTL]; only the first ttl is used, and it is ignored for the other intents.
+// An unknown intent results in an empty header.
+func ForIntent(i Intent, ttl ...time.Duration) *Header {
+	h := &Header{}
+	lifetime := func(d time.Duration) time.Duration {
+		if len(ttl) > 0 {
+			return ttl[0]
+		}
+		return d
+	}
+	switch i {
+	case IntentNoStore:
+		h.NoStore = true
+	case IntentNoCache:
+		h.NoCache = true
+	case IntentImmutableAsset:
+		h.SetMaxAge(lifetime(DefaultImmutableAssetTTL))
+		h.Public = true
+		h.Immutable = true
+	case IntentPrivateShort:
+		h.SetMaxAge(lifetime(DefaultShortTTL))
+		h.Private = true
+	case IntentPublicShort:
+		h.SetMaxAge(lifetime(DefaultShortTTL))
+		h.Public = true
+	}
+	return h
+}
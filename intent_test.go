@@ -0,0 +1,35 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestForIntent(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		intent cachecontrolheader.Intent
+		ttl    []time.Duration
+		want   string
+	}{
+		{intent: cachecontrolheader.IntentNoStore, want: "no-store"},
+		{intent: cachecontrolheader.IntentNoStore, ttl: []time.Duration{time.Hour}, want: "no-store"},
+		{intent: cachecontrolheader.IntentNoCache, want: "no-cache"},
+		{intent: cachecontrolheader.IntentImmutableAsset, want: "max-age=31536000, public, immutable"},
+		{intent: cachecontrolheader.IntentImmutableAsset, ttl: []time.Duration{30 * 24 * time.Hour}, want: "max-age=2592000, public, immutable"},
+		{intent: cachecontrolheader.IntentPrivateShort, want: "max-age=60, private"},
+		{intent: cachecontrolheader.IntentPrivateShort, ttl: []time.Duration{10 * time.Second, time.Hour}, want: "max-age=10, private"},
+		{intent: cachecontrolheader.IntentPublicShort, want: "max-age=60, public"},
+		{intent: cachecontrolheader.Intent(-1), want: ""},
+	} {
+		tt := tt
+		t.Run(tt.intent.String(), func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.ForIntent(tt.intent, tt.ttl...).String(); got != tt.want {
+				t.Errorf("ForIntent(%v, %v).String() = %q, want %q", tt.intent, tt.ttl, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package cachecontrolheader
+
+import "fmt"
+
+// Severity represents how serious a [Problem] is.
+type Severity int
+
+const (
+	// SeverityError means the directive is unknown or has an invalid value,
+	// so it is dropped from the parsed header.
+	SeverityError Severity = iota
+	// SeverityWarning means the directive is valid but questionable,
+	// e.g. it conflicts with another directive.
+	SeverityWarning
+)
+
+// String returns a string representation of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Problem represents a problem found in a Cache-Control header by [Lint].
+type Problem struct {
+	Severity  Severity // how serious the problem is
+	Directive string   // name of the directive causing the problem
+	Message   string   // human-readable description of the problem
+}
+
+// String returns a string representation of the problem.
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Severity, p.Message)
+}
+
+// Lint parses a Cache-Control header like [Parse] and reports every problem found in it.
+// Unknown directives and invalid values are reported as errors and dropped from the header,
+// while conflicting directives, known directives not written in lowercase
+// and the obsolete pre-check and post-check are reported as warnings and kept.
+// Advisory warnings can be enabled by options, e.g. [ReportRedundantPublic].
+func Lint(header string, opts ...lintOption) (*Header, []Problem) {
+	return lint(header, option{}, opts)
+}
+
+// lint does the linting of Lint, parsing the header with o on top of the lenient options of Lint.
+func lint(header string, o option, opts []lintOption) (*Header, []Problem) {
+	c := lintConfig{}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	var ps []Problem
+	for _, opt := range []parseOption{IgnoreInvalidValues(), IgnoreUnknownDirectives(), ReportNonCanonicalCase()} {
+		opt(&o)
+	}
+	o.problems = &ps
+	h, _ := parseWithOption(header, o)
+	for _, d := range h.conflicts() {
+		ps = append(ps, Problem{
+			Severity:  SeverityWarning,
+			Directive: d,
+			Message:   conflictError(d).Error(),
+		})
+	}
+	if c.reportRedundantPublic && h.Public && !h.NoStore {
+		for _, d := range []string{dSMaxAge, dMaxAge} {
+			if *h.duration(d) != nil {
+				ps = append(ps, Problem{
+					Severity:  SeverityWarning,
+					Directive: dPublic,
+					Message:   fmt.Sprintf("redundant directive: %s with %s", dPublic, d),
+				})
+				break
+			}
+		}
+	}
+	return h, ps
+}
+
+// SafeParse parses a Cache-Control header leniently like [Parse], and reports what was dropped or tolerated,
+// for servers that log problems but proceed. It never fails.
+// Unknown directives and invalid values are reported as errors, and tolerated syntax problems such as obs-fold as warnings.
+// Unlike [Lint], it does not report questionable but valid directives, e.g. conflicting ones.
+func SafeParse(header string) (*Header, []Problem) {
+	var ps []Problem
+	h, _ := parse(header, IgnoreInvalidValues(), IgnoreUnknownDirectives(), func(o *option) {
+		o.problems = &ps
+	})
+	return h, ps
+}
+
+// ReportRedundantPublic makes [Lint] warn about public combined with s-maxage or max-age,
+// which already make the response cacheable by shared caches.
+// Note that with max-age alone, public still allows shared caches to store responses
+// to requests with an Authorization header (RFC 9111 Section 3.5).
+func ReportRedundantPublic() lintOption {
+	return func(c *lintConfig) {
+		c.reportRedundantPublic = true
+	}
+}
+
+type lintConfig struct {
+	reportRedundantPublic bool
+}
+type lintOption func(*lintConfig)
@@ -0,0 +1,230 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header       string
+		wantHeader   *cachecontrolheader.Header
+		wantProblems []cachecontrolheader.Problem
+	}{
+		{
+			header: "max-age=3600, private",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(3600 * time.Second),
+				Private: true,
+			},
+		},
+		{
+			header: "no-store, max-age=invalid, unknown, public",
+			wantHeader: &cachecontrolheader.Header{
+				NoStore: true,
+				Public:  true,
+			},
+			wantProblems: []cachecontrolheader.Problem{
+				{
+					Severity:  cachecontrolheader.SeverityError,
+					Directive: "max-age",
+					Message:   `failed to parse the value of directive(max-age=invalid): invalid delta-seconds "invalid"`,
+				},
+				{
+					Severity:  cachecontrolheader.SeverityError,
+					Directive: "unknown",
+					Message:   "unknown directive: unknown",
+				},
+				{
+					Severity:  cachecontrolheader.SeverityWarning,
+					Directive: "public",
+					Message:   "conflicting directives: no-store and public",
+				},
+			},
+		},
+		{
+			header: "max-age=(60)",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(60 * time.Second),
+			},
+			wantProblems: []cachecontrolheader.Problem{
+				{
+					Severity:  cachecontrolheader.SeverityWarning,
+					Directive: "max-age",
+					Message:   "directive(max-age) has a bracketed value: max-age=(60)",
+				},
+			},
+		},
+		{
+			header: "no-cache, post-check=0, pre-check=0",
+			wantHeader: &cachecontrolheader.Header{
+				NoCache: true,
+			},
+			wantProblems: []cachecontrolheader.Problem{
+				{
+					Severity:  cachecontrolheader.SeverityWarning,
+					Directive: "post-check",
+					Message:   "obsolete directive: post-check",
+				},
+				{
+					Severity:  cachecontrolheader.SeverityWarning,
+					Directive: "pre-check",
+					Message:   "obsolete directive: pre-check",
+				},
+			},
+		},
+		{
+			header: "Max-Age=60, X-Unknown",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(60 * time.Second),
+			},
+			wantProblems: []cachecontrolheader.Problem{
+				{
+					Severity:  cachecontrolheader.SeverityWarning,
+					Directive: "max-age",
+					Message:   "non-canonical case of directive: Max-Age",
+				},
+				{
+					Severity:  cachecontrolheader.SeverityError,
+					Directive: "x-unknown",
+					Message:   "unknown directive: x-unknown",
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, ps := cachecontrolheader.Lint(tt.header)
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantProblems, ps); diff != "" {
+				t.Errorf("Problems mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLint_ReportRedundantPublic(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header       string
+		wantProblems []cachecontrolheader.Problem
+	}{
+		{
+			header: "public, s-maxage=60",
+			wantProblems: []cachecontrolheader.Problem{
+				{
+					Severity:  cachecontrolheader.SeverityWarning,
+					Directive: "public",
+					Message:   "redundant directive: public with s-maxage",
+				},
+			},
+		},
+		{
+			header: "public, max-age=60, s-maxage=60",
+			wantProblems: []cachecontrolheader.Problem{
+				{
+					Severity:  cachecontrolheader.SeverityWarning,
+					Directive: "public",
+					Message:   "redundant directive: public with s-maxage",
+				},
+			},
+		},
+		{
+			header: "max-age=60, public",
+			wantProblems: []cachecontrolheader.Problem{
+				{
+					Severity:  cachecontrolheader.SeverityWarning,
+					Directive: "public",
+					Message:   "redundant directive: public with max-age",
+				},
+			},
+		},
+		{
+			header: "public",
+		},
+		{
+			header: "s-maxage=60",
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			_, ps := cachecontrolheader.Lint(tt.header, cachecontrolheader.ReportRedundantPublic())
+			if diff := cmp.Diff(tt.wantProblems, ps); diff != "" {
+				t.Errorf("Problems mismatch (-want +got):\n%s", diff)
+			}
+			if _, ps := cachecontrolheader.Lint(tt.header); len(ps) != 0 {
+				t.Errorf("Lint() without the option reported %v", ps)
+			}
+		})
+	}
+}
+
+func TestSafeParse(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header       string
+		wantHeader   *cachecontrolheader.Header
+		wantProblems []cachecontrolheader.Problem
+	}{
+		{
+			header: "max-age=60, public",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(60 * time.Second),
+				Public: true,
+			},
+		},
+		{
+			header: "Max-Age=invalid, unknown, no-store, public, no-cache=1x",
+			wantHeader: &cachecontrolheader.Header{
+				NoCacheFields: []string{"1x"},
+				NoStore:       true,
+				Public:        true,
+			},
+			wantProblems: []cachecontrolheader.Problem{
+				{
+					Severity:  cachecontrolheader.SeverityError,
+					Directive: "max-age",
+					Message:   `failed to parse the value of directive(max-age=invalid): invalid delta-seconds "invalid"`,
+				},
+				{
+					Severity:  cachecontrolheader.SeverityError,
+					Directive: "unknown",
+					Message:   "unknown directive: unknown",
+				},
+			},
+		},
+		{
+			header: "no-store=1",
+			wantHeader: &cachecontrolheader.Header{
+				NoStore: true,
+			},
+			wantProblems: []cachecontrolheader.Problem{
+				{
+					Severity:  cachecontrolheader.SeverityWarning,
+					Directive: "no-store",
+					Message:   "directive(no-store) takes no value: no-store=1",
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, ps := cachecontrolheader.SafeParse(tt.header)
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantProblems, ps); diff != "" {
+				t.Errorf("Problems mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package cachecontrolheader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DirectiveKind represents the kind of value a directive takes, returned by [Header.Lookup].
+type DirectiveKind int
+
+const (
+	// DirectiveKindUnknown is returned when the directive is not set.
+	DirectiveKindUnknown DirectiveKind = iota
+	// DirectiveKindBoolean means the directive takes no value, e.g. no-store.
+	DirectiveKindBoolean
+	// DirectiveKindDuration means the directive takes delta-seconds, e.g. max-age.
+	DirectiveKindDuration
+	// DirectiveKindFieldList means the directive takes a list of field names, e.g. private="Set-Cookie".
+	DirectiveKindFieldList
+	// DirectiveKindExtension means the directive is an extension unknown to this package.
+	DirectiveKindExtension
+)
+
+// String returns a string representation of the directive kind.
+func (k DirectiveKind) String() string {
+	switch k {
+	case DirectiveKindUnknown:
+		return "unknown"
+	case DirectiveKindBoolean:
+		return "boolean"
+	case DirectiveKindDuration:
+		return "duration"
+	case DirectiveKindFieldList:
+		return "field-list"
+	case DirectiveKindExtension:
+		return "extension"
+	default:
+		return fmt.Sprintf("DirectiveKind(%d)", int(k))
+	}
+}
+
+// Lookup returns the value and the kind of the directive named name, compared case-insensitively.
+// ok is false if the directive is not set.
+//
+// A duration directive has its delta-seconds as the value, e.g. "3600", and a boolean directive an empty value.
+// no-cache and private with field names have the comma-separated field names as the value,
+// and are reported as [DirectiveKindFieldList] even when also set unqualified.
+// An extension has its value with quoted-string unquoted; for an extension captured more than once,
+// the first one is returned.
+func (h *Header) Lookup(name string) (value string, kind DirectiveKind, ok bool) {
+	name = strings.ToLower(name)
+	if fs := h.fields(name); fs != nil && len(*fs) > 0 {
+		return strings.Join(*fs, ", "), DirectiveKindFieldList, true
+	}
+	if b := h.flag(name); b != nil {
+		if !*b {
+			return "", DirectiveKindUnknown, false
+		}
+		return "", DirectiveKindBoolean, true
+	}
+	if d := h.duration(name); d != nil {
+		if *d == nil {
+			return "", DirectiveKindUnknown, false
+		}
+		return strconv.Itoa(int((*d).Seconds())), DirectiveKindDuration, true
+	}
+	for _, e := range h.extensions {
+		if strings.EqualFold(e.name, name) {
+			return unquote(e.value), DirectiveKindExtension, true
+		}
+	}
+	return "", DirectiveKindUnknown, false
+}
+
+// DirectiveState reports whether the directive named name, compared case-insensitively, is set in any form,
+// and whether it is truthy, i.e. it enables its effect:
+//
+//   - a boolean directive is truthy when set; the Cache-Control syntax has no way to write false,
+//     so a false field is always unset,
+//   - a duration directive is truthy when set to a non-zero value, so max-age=0 is set but not truthy,
+//     unlike a missing max-age, and
+//   - no-cache and private with field names, and extensions, are truthy when set.
+//
+// Merging logic can use it to tell a directive explicitly set to a zero value from a missing one.
+func (h *Header) DirectiveState(name string) (set bool, truthy bool) {
+	_, kind, ok := h.Lookup(name)
+	if !ok {
+		return false, false
+	}
+	if kind == DirectiveKindDuration {
+		return true, **h.duration(strings.ToLower(name)) != 0
+	}
+	return true, true
+}
@@ -0,0 +1,72 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestHeader_Lookup(t *testing.T) {
+	t.Parallel()
+	const header = `max-age=3600, no-store, private="Set-Cookie, Authorization", no-cache, X-Ext="a b", x-ext=2`
+	h, err := cachecontrolheader.ParseStrict(header, cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name      string
+		wantValue string
+		wantKind  cachecontrolheader.DirectiveKind
+		wantOK    bool
+	}{
+		{name: "max-age", wantValue: "3600", wantKind: cachecontrolheader.DirectiveKindDuration, wantOK: true},
+		{name: "No-Store", wantKind: cachecontrolheader.DirectiveKindBoolean, wantOK: true},
+		{name: "no-cache", wantKind: cachecontrolheader.DirectiveKindBoolean, wantOK: true},
+		{name: "private", wantValue: "Set-Cookie, Authorization", wantKind: cachecontrolheader.DirectiveKindFieldList, wantOK: true},
+		{name: "x-ext", wantValue: "a b", wantKind: cachecontrolheader.DirectiveKindExtension, wantOK: true},
+		{name: "s-maxage"},
+		{name: "public"},
+		{name: "x-other"},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			value, kind, ok := h.Lookup(tt.name)
+			if value != tt.wantValue || kind != tt.wantKind || ok != tt.wantOK {
+				t.Errorf("Header.Lookup(%q) = (%q, %v, %v), want (%q, %v, %v)", tt.name, value, kind, ok, tt.wantValue, tt.wantKind, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHeader_DirectiveState(t *testing.T) {
+	t.Parallel()
+	const header = `max-age=0, s-maxage=60, no-store, private="Set-Cookie", x-ext=0`
+	h, err := cachecontrolheader.ParseStrict(header, cachecontrolheader.CaptureUnknownDirectives())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name       string
+		wantSet    bool
+		wantTruthy bool
+	}{
+		{name: "max-age", wantSet: true, wantTruthy: false},
+		{name: "min-fresh", wantSet: false, wantTruthy: false},
+		{name: "S-MaxAge", wantSet: true, wantTruthy: true},
+		{name: "no-store", wantSet: true, wantTruthy: true},
+		{name: "public", wantSet: false, wantTruthy: false},
+		{name: "private", wantSet: true, wantTruthy: true},
+		{name: "x-ext", wantSet: true, wantTruthy: true},
+		{name: "x-other", wantSet: false, wantTruthy: false},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			set, truthy := h.DirectiveState(tt.name)
+			if set != tt.wantSet || truthy != tt.wantTruthy {
+				t.Errorf("Header.DirectiveState(%q) = (%v, %v), want (%v, %v)", tt.name, set, truthy, tt.wantSet, tt.wantTruthy)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package cachecontrolheader
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FromMap builds a header from a map of directive names to values, e.g. loaded from structured config.
+// Duration directives take delta-seconds like "3600", boolean directives take an empty value,
+// and no-cache and private take either an empty value or a comma-separated field-name list.
+// Names are case-insensitive. It returns an error for an unknown name or an invalid value.
+func FromMap(m map[string]string) (*Header, error) {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names) // for a deterministic error
+
+	h := &Header{}
+	for _, name := range names {
+		value := m[name]
+		key := strings.ToLower(name)
+		if d := h.duration(key); d != nil {
+			v, err := parseDeltaSeconds(value)
+			if err != nil {
+				return nil, &InvalidValueError{Directive: key, Value: value, Err: err}
+			}
+			*d = &v
+			continue
+		}
+		if fs := h.fields(key); fs != nil && value != "" {
+			*fs = splitFieldNames(value)
+			continue
+		}
+		b := h.flag(key)
+		if b == nil {
+			return nil, fmt.Errorf("unknown directive: %s", key)
+		}
+		if value != "" {
+			return nil, fmt.Errorf("directive(%s) takes no value: %s=%s", key, key, value)
+		}
+		*b = true
+	}
+	return h, nil
+}
+
+// Values returns the directives of the header as a multi-map from lowercase names to values,
+// for code that already handles [url.Values].
+// Duration directives map to delta-seconds like "3600" and boolean directives to an empty value.
+// The field names of no-cache and private are separate values, following an empty value
+// if the directive is also set unqualified. Extension values are unquoted as in [Header.Extensions].
+func (h *Header) Values() url.Values {
+	v := url.Values{}
+	for _, name := range directiveNames {
+		if d := h.duration(name); d != nil && *d != nil {
+			v.Add(name, strconv.Itoa(int((*d).Seconds())))
+			continue
+		}
+		if b := h.flag(name); b != nil && *b {
+			v.Add(name, "")
+		}
+		if fs := h.fields(name); fs != nil {
+			for _, f := range *fs {
+				v.Add(name, f)
+			}
+		}
+	}
+	for _, e := range h.extensions {
+		v.Add(strings.ToLower(e.name), unquote(e.value))
+	}
+	return v
+}
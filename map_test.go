@@ -0,0 +1,123 @@
+package cachecontrolheader_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestFromMap(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name       string
+		m          map[string]string
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			name: "mixed",
+			m: map[string]string{
+				"max-age":         "3600",
+				"S-Maxage":        "60",
+				"must-revalidate": "",
+				"private":         "Set-Cookie, Authorization",
+				"no-cache":        "",
+			},
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:         durationPtr(3600 * time.Second),
+				SMaxAge:        durationPtr(60 * time.Second),
+				MustRevalidate: true,
+				PrivateFields:  []string{"Set-Cookie", "Authorization"},
+				NoCache:        true,
+			},
+		},
+		{
+			name:       "empty",
+			m:          map[string]string{},
+			wantHeader: &cachecontrolheader.Header{},
+		},
+		{
+			name:    "unknown",
+			m:       map[string]string{"max-age": "60", "unknown": ""},
+			wantErr: true,
+		},
+		{
+			name:    "invalid duration",
+			m:       map[string]string{"max-age": "1h"},
+			wantErr: true,
+		},
+		{
+			name:    "empty duration",
+			m:       map[string]string{"max-age": ""},
+			wantErr: true,
+		},
+		{
+			name:    "boolean with value",
+			m:       map[string]string{"no-store": "true"},
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.FromMap(tt.m)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeader_Values(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   url.Values
+	}{
+		{
+			header: "",
+			want:   url.Values{},
+		},
+		{
+			header: "no-store, Public",
+			want:   url.Values{"no-store": {""}, "public": {""}},
+		},
+		{
+			header: "max-age=3600, s-maxage=0",
+			want:   url.Values{"max-age": {"3600"}, "s-maxage": {"0"}},
+		},
+		{
+			header: `private="Set-Cookie, Authorization", no-cache, no-cache="X-A"`,
+			want: url.Values{
+				"private":  {"Set-Cookie", "Authorization"},
+				"no-cache": {"", "X-A"},
+			},
+		},
+		{
+			header: `X-Ext="a b", x-flag`,
+			want:   url.Values{"x-ext": {"a b"}, "x-flag": {""}},
+		},
+		{
+			header: `x-t="say \"hi\"", x-u=token, x-t=`,
+			want:   url.Values{"x-t": {`say "hi"`, ""}, "x-u": {"token"}},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, h.Values()); diff != "" {
+				t.Errorf("Header.Values() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package cachecontrolheader
+
+import (
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// ParseMIME parses the Cache-Control fields of a MIME header like [ParseStrict].
+// Multiple field lines are combined into one list, as allowed by RFC 9110 Section 5.3.
+// The field name is matched case-insensitively, so keys not in the canonical form,
+// e.g. set on the map directly, are found too; such keys are combined after the canonical one,
+// in sorted order.
+func ParseMIME(h textproto.MIMEHeader, opts ...parseOption) (*Header, error) {
+	const key = "Cache-Control"
+	values := h[key]
+	var others []string
+	for k := range h {
+		if k != key && strings.EqualFold(k, key) {
+			others = append(others, k)
+		}
+	}
+	if len(others) > 0 {
+		sort.Strings(others)
+		values = append([]string(nil), values...)
+		for _, k := range others {
+			values = append(values, h[k]...)
+		}
+	}
+	return parse(strings.Join(values, ", "), opts...)
+}
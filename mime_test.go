@@ -0,0 +1,65 @@
+package cachecontrolheader_test
+
+import (
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestParseMIME(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name       string
+		header     textproto.MIMEHeader
+		wantHeader *cachecontrolheader.Header
+		wantErr    bool
+	}{
+		{
+			name: "multiple values",
+			header: textproto.MIMEHeader{
+				"Cache-Control": {"max-age=60", "no-transform, public"},
+			},
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:      durationPtr(60 * time.Second),
+				NoTransform: true,
+				Public:      true,
+			},
+		},
+		{
+			name: "non-canonical key",
+			header: textproto.MIMEHeader{
+				"Cache-Control": {"max-age=60"},
+				"cache-control": {"private"},
+			},
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(60 * time.Second),
+				Private: true,
+			},
+		},
+		{
+			name:       "missing",
+			header:     textproto.MIMEHeader{"Content-Type": {"text/plain"}},
+			wantHeader: &cachecontrolheader.Header{},
+		},
+		{
+			name:    "invalid value",
+			header:  textproto.MIMEHeader{"Cache-Control": {"max-age=60", "max-age=invalid"}},
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseMIME(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
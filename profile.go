@@ -0,0 +1,33 @@
+package cachecontrolheader
+
+import "strings"
+
+// Profile describes the quirks of a particular kind of cache, e.g. a CDN,
+// for parsing a header as that cache understands it. It is applied by the [WithProfile] option.
+//
+// Profiles are plain values: to match another cache, define a Profile with the directives it does not honor.
+type Profile struct {
+	Name string // name of the profile for diagnostics
+
+	// IgnoredDirectives are the names of the known directives the cache does not honor.
+	// They are dropped while parsing, as if they were not in the header.
+	IgnoredDirectives []string
+}
+
+// ProfilePrivateCache is the profile of a private cache, e.g. a browser cache,
+// which does not honor the directives only for shared caches: s-maxage and proxy-revalidate
+// (RFC 9111 Sections 5.2.2.8 and 5.2.2.10).
+var ProfilePrivateCache = Profile{
+	Name:              "private-cache",
+	IgnoredDirectives: []string{dProxyRevalidate, dSMaxAge},
+}
+
+// WithProfile makes parsing follow the profile p.
+// The directives ignored by p are dropped silently, whatever the other options are.
+func WithProfile(p Profile) parseOption {
+	return func(o *option) {
+		for _, name := range p.IgnoredDirectives {
+			o.ignoredDirectives = append(o.ignoredDirectives, strings.ToLower(name))
+		}
+	}
+}
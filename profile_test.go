@@ -0,0 +1,67 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestParseStrict_WithProfile(t *testing.T) {
+	t.Parallel()
+	cdn := cachecontrolheader.Profile{
+		Name:              "cdn",
+		IgnoredDirectives: []string{"S-Maxage"},
+	}
+	for _, tt := range []struct {
+		name       string
+		header     string
+		profile    cachecontrolheader.Profile
+		wantHeader *cachecontrolheader.Header
+		wantString string
+	}{
+		{
+			name:    "custom profile drops s-maxage",
+			header:  "max-age=60, s-maxage=3600, proxy-revalidate",
+			profile: cdn,
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:          durationPtr(60 * time.Second),
+				ProxyRevalidate: true,
+			},
+			wantString: "max-age=60, proxy-revalidate",
+		},
+		{
+			name:       "invalid value of an ignored directive",
+			header:     "max-age=60, s-maxage=invalid",
+			profile:    cdn,
+			wantHeader: &cachecontrolheader.Header{MaxAge: durationPtr(60 * time.Second)},
+			wantString: "max-age=60",
+		},
+		{
+			name:    "private cache",
+			header:  "max-age=60, S-MaxAge=3600, proxy-revalidate, public",
+			profile: cachecontrolheader.ProfilePrivateCache,
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(60 * time.Second),
+				Public: true,
+			},
+			wantString: "max-age=60, public",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h, err := cachecontrolheader.ParseStrict(tt.header, cachecontrolheader.WithProfile(tt.profile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if got := h.StringPreserveOrder(); got != tt.wantString {
+				t.Errorf("Header.StringPreserveOrder() = %q, want %q", got, tt.wantString)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package cachecontrolheader
+
+import "strings"
+
+// Span locates a directive in a Cache-Control header, e.g. for highlighting it in a UI.
+type Span struct {
+	Name  string // lowercase name of the directive
+	Start int    // byte offset of the directive in the header
+	End   int    // byte offset just after the directive, excluding trailing whitespace
+}
+
+// ParseWithSpans parses a Cache-Control header like [Parse], and also returns the span of every directive
+// in the header, including the ones dropped as unknown or invalid, in the order they appear.
+// The spans are byte offsets into header as given, before any normalization such as unfolding obs-fold,
+// but exclude a leading BOM and obs-fold around the directives, so that they name the same directives as Parse.
+func ParseWithSpans(header string) (*Header, []Span) {
+	var spans []Span
+	t := tokenizer{s: header}
+	for {
+		tok, ok, _ := t.next()
+		if !ok {
+			break
+		}
+		if span, ok := spanOf(tok); ok {
+			spans = append(spans, span)
+		}
+	}
+	return Parse(header), spans
+}
+
+// spanOf returns the span of the directive tok, which is tokenized from the header before normalization.
+// A leading BOM and obs-fold around the name are excluded, since [Parse] strips them before tokenizing.
+// It returns false if nothing is left, i.e. the directive is an empty list element once normalized.
+func spanOf(tok token) (Span, bool) {
+	name := tok.name
+	if tok.start == 0 {
+		name = strings.TrimPrefix(name, "\uFEFF")
+	}
+	name = strings.TrimLeft(name, " \t\r\n")
+	start := tok.start + len(tok.name) - len(name)
+	end := tok.end
+	name = strings.TrimRight(name, " \t\r\n")
+	if !tok.hasValue {
+		end = start + len(name)
+	}
+	if name == "" {
+		return Span{}, false
+	}
+	return Span{Name: strings.ToLower(name), Start: start, End: end}, true
+}
@@ -0,0 +1,99 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestParseWithSpans(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header     string
+		wantHeader *cachecontrolheader.Header
+		wantSpans  []cachecontrolheader.Span
+	}{
+		{
+			header: `Max-Age=60 , , no-cache="Set-Cookie, X-A", unknown,private`,
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:        durationPtr(60 * time.Second),
+				NoCacheFields: []string{"Set-Cookie", "X-A"},
+				Private:       true,
+			},
+			wantSpans: []cachecontrolheader.Span{
+				{Name: "max-age", Start: 0, End: 10},
+				{Name: "no-cache", Start: 15, End: 41},
+				{Name: "unknown", Start: 43, End: 50},
+				{Name: "private", Start: 51, End: 58},
+			},
+		},
+		{
+			header: "max-age= , s-maxage = 5\t",
+			wantHeader: &cachecontrolheader.Header{
+				SMaxAge: durationPtr(5 * time.Second),
+			},
+			wantSpans: []cachecontrolheader.Span{
+				{Name: "max-age", Start: 0, End: 8},
+				{Name: "s-maxage", Start: 11, End: 23},
+			},
+		},
+		{
+			header:     `x="unterminated`,
+			wantHeader: &cachecontrolheader.Header{},
+			wantSpans: []cachecontrolheader.Span{
+				{Name: "x", Start: 0, End: 15},
+			},
+		},
+		{
+			header: "max-age=60,\r\n private",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(60 * time.Second),
+				Private: true,
+			},
+			wantSpans: []cachecontrolheader.Span{
+				{Name: "max-age", Start: 0, End: 10},
+				{Name: "private", Start: 14, End: 21},
+			},
+		},
+		{
+			header: "no-store\r\n\t, public,\r\n ",
+			wantHeader: &cachecontrolheader.Header{
+				NoStore: true,
+				Public:  true,
+			},
+			wantSpans: []cachecontrolheader.Span{
+				{Name: "no-store", Start: 0, End: 8},
+				{Name: "public", Start: 13, End: 19},
+			},
+		},
+		{
+			header: "\ufeffmax-age=60, public",
+			wantHeader: &cachecontrolheader.Header{
+				MaxAge: durationPtr(60 * time.Second),
+				Public: true,
+			},
+			wantSpans: []cachecontrolheader.Span{
+				{Name: "max-age", Start: 3, End: 13},
+				{Name: "public", Start: 15, End: 21},
+			},
+		},
+		{
+			header:     "",
+			wantHeader: &cachecontrolheader.Header{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			h, spans := cachecontrolheader.ParseWithSpans(tt.header)
+			if diff := cmp.Diff(tt.wantHeader, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantSpans, spans); diff != "" {
+				t.Errorf("Spans mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
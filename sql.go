@@ -0,0 +1,31 @@
+package cachecontrolheader
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Scan implements [database/sql.Scanner].
+// It parses a string or []byte leniently like [Parse], and sets an empty header for NULL.
+// Unknown directives are kept as extensions, so that a header stored by [Header.Value] is scanned back intact.
+func (h *Header) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*h = Header{}
+	case string:
+		p, _ := parse(v, IgnoreInvalidValues(), CaptureUnknownDirectives())
+		*h = *p
+	case []byte:
+		p, _ := ParseBytes(v, IgnoreInvalidValues(), CaptureUnknownDirectives())
+		*h = *p
+	default:
+		return fmt.Errorf("cannot scan %T into Header", src)
+	}
+	return nil
+}
+
+// Value implements [database/sql/driver.Valuer].
+// It returns the string representation of the header by [Header.String].
+func (h Header) Value() (driver.Value, error) {
+	return h.String(), nil
+}
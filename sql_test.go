@@ -0,0 +1,106 @@
+package cachecontrolheader_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+var (
+	_ sql.Scanner   = (*cachecontrolheader.Header)(nil)
+	_ driver.Valuer = cachecontrolheader.Header{}
+)
+
+func TestHeader_Scan(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name    string
+		src     interface{}
+		want    *cachecontrolheader.Header
+		wantErr bool
+	}{
+		{
+			name: "string",
+			src:  "max-age=60, private, unknown",
+			want: &cachecontrolheader.Header{
+				MaxAge:  durationPtr(60 * time.Second),
+				Private: true,
+			},
+		},
+		{
+			name: "bytes",
+			src:  []byte("no-store, max-age=invalid"),
+			want: &cachecontrolheader.Header{
+				NoStore: true,
+			},
+		},
+		{
+			name: "nil",
+			src:  nil,
+			want: &cachecontrolheader.Header{},
+		},
+		{
+			name:    "unsupported type",
+			src:     42,
+			want:    &cachecontrolheader.Header{Public: true},
+			wantErr: true,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := &cachecontrolheader.Header{Public: true}
+			err := h.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error: %v, want: %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHeader_Value_roundTrip(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		name string
+		src  func(string) interface{}
+	}{
+		{name: "string", src: func(s string) interface{} { return s }},
+		{name: "bytes", src: func(s string) interface{} { return []byte(s) }},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			want, err := cachecontrolheader.ParseStrict(`max-age=3600, must-revalidate, private="Set-Cookie", x-ext=1`, cachecontrolheader.CaptureUnknownDirectives())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var valuer driver.Valuer = *want
+			v, err := valuer.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := v, driver.Value(`max-age=3600, must-revalidate, private="Set-Cookie", x-ext=1`); got != want {
+				t.Errorf("Header.Value() = %v, want %v", got, want)
+			}
+
+			var h cachecontrolheader.Header
+			var scanner sql.Scanner = &h
+			if err := scanner.Scan(tt.src(v.(string))); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, &h, ignoreUnexported); diff != "" {
+				t.Errorf("Header mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(want.Extensions(), h.Extensions()); diff != "" {
+				t.Errorf("Header.Extensions() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package cachecontrolheader
+
+// SharedCacheSafe reports whether a response with the header may be stored by a shared cache
+// and served to other users. It returns false when:
+//
+//   - private is set, whether unqualified or qualified with field names,
+//   - no-store is set, or
+//   - authenticated is true, i.e. the request had an Authorization header,
+//     and none of public, s-maxage and must-revalidate explicitly allows it (RFC 9111 Section 3.5).
+func (h *Header) SharedCacheSafe(authenticated bool) bool {
+	if h.Private || len(h.PrivateFields) > 0 || h.NoStore {
+		return false
+	}
+	if authenticated {
+		return h.Public || h.SMaxAge != nil || h.MustRevalidate
+	}
+	return true
+}
+
+// CacheableWithAuthorization reports whether a shared cache may store a response with the header
+// to a request with an Authorization header. By RFC 9111 Section 3.5, such a response is not stored
+// unless one of these directives explicitly allows it:
+//
+//   - public,
+//   - s-maxage, or
+//   - must-revalidate.
+//
+// Even then, private and no-store prevent storing. It is equivalent to SharedCacheSafe(true).
+func (h *Header) CacheableWithAuthorization() bool {
+	return h.SharedCacheSafe(true)
+}
+
+// Storability reports whether a private cache and a shared cache may store a response with the header
+// (RFC 9111 Section 3), assuming the request had no Authorization header:
+//
+//   - no-store forbids both,
+//   - private forbids a shared cache, here even when qualified with field names as in [Header.SharedCacheSafe], and
+//   - otherwise both may store it; public only matters for authenticated requests, see [Header.CacheableWithAuthorization].
+func (h *Header) Storability() (privateCache bool, sharedCache bool) {
+	return !h.NoStore, h.SharedCacheSafe(false)
+}
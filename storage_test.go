@@ -0,0 +1,88 @@
+package cachecontrolheader_test
+
+import (
+	"testing"
+
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestHeader_SharedCacheSafe(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header        string
+		authenticated bool
+		want          bool
+	}{
+		{header: "max-age=60", want: true},
+		{header: "public, max-age=60", want: true},
+		{header: "private, max-age=60", want: false},
+		{header: `private="Set-Cookie", max-age=60`, want: false},
+		{header: "no-store", want: false},
+		{header: "public, no-store", want: false},
+		{header: "max-age=60", authenticated: true, want: false},
+		{header: "public, max-age=60", authenticated: true, want: true},
+		{header: "s-maxage=60", authenticated: true, want: true},
+		{header: "must-revalidate", authenticated: true, want: true},
+		{header: "public, private", authenticated: true, want: false},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.Parse(tt.header).SharedCacheSafe(tt.authenticated); got != tt.want {
+				t.Errorf("Header.SharedCacheSafe(%v) = %v, want %v", tt.authenticated, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_CacheableWithAuthorization(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header string
+		want   bool
+	}{
+		{header: "", want: false},
+		{header: "max-age=60", want: false},
+		{header: "no-cache, proxy-revalidate", want: false},
+		{header: "public", want: true},
+		{header: "s-maxage=60", want: true},
+		{header: "must-revalidate", want: true},
+		{header: "public, no-store", want: false},
+		{header: `s-maxage=60, private="Set-Cookie"`, want: false},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			if got := cachecontrolheader.Parse(tt.header).CacheableWithAuthorization(); got != tt.want {
+				t.Errorf("Header.CacheableWithAuthorization() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeader_Storability(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct {
+		header      string
+		wantPrivate bool
+		wantShared  bool
+	}{
+		{header: "", wantPrivate: true, wantShared: true},
+		{header: "public", wantPrivate: true, wantShared: true},
+		{header: "private", wantPrivate: true, wantShared: false},
+		{header: `private="Set-Cookie"`, wantPrivate: true, wantShared: false},
+		{header: "no-store", wantPrivate: false, wantShared: false},
+		{header: "public, no-store", wantPrivate: false, wantShared: false},
+		{header: "private, no-store", wantPrivate: false, wantShared: false},
+		{header: "public, private", wantPrivate: true, wantShared: false},
+	} {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			t.Parallel()
+			gotPrivate, gotShared := cachecontrolheader.Parse(tt.header).Storability()
+			if gotPrivate != tt.wantPrivate || gotShared != tt.wantShared {
+				t.Errorf("Header.Storability() = (%v, %v), want (%v, %v)", gotPrivate, gotShared, tt.wantPrivate, tt.wantShared)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package cachecontrolheader
+
+import (
+	"errors"
+	"strings"
+)
+
+// token is a directive of a Cache-Control header as written, before its semantics are checked.
+type token struct {
+	name     string // name as written
+	value    string // value with quoted-string unquoted
+	rawValue string // value as written
+	hasValue bool   // whether the directive has `=` followed by a value
+	start    int    // byte offset of the directive in the header
+	end      int    // byte offset just after the directive, excluding trailing whitespace
+}
+
+// tokenizer splits a Cache-Control header into directives.
+// It follows the list syntax of RFC 9110 Section 5.6.1: directives are separated by commas
+// surrounded by optional whitespace, and empty list elements are skipped.
+// Values are either a token or a quoted-string (RFC 9110 Section 5.6.4).
+type tokenizer struct {
+	s   string
+	pos int
+}
+
+// next returns the next directive in the header.
+// It returns false when there are no more directives.
+// On a syntax error, it returns the error and skips to the next directive.
+func (t *tokenizer) next() (token, bool, error) {
+	t.skip(func(c byte) bool { return isOWS(c) || c == ',' })
+	if t.pos >= len(t.s) {
+		return token{}, false, nil
+	}
+
+	start := t.pos
+	t.skip(func(c byte) bool { return c != ',' && c != '=' })
+	tok := token{name: strings.TrimRight(t.s[start:t.pos], " \t"), start: start}
+	tok.end = start + len(tok.name)
+	if t.pos >= len(t.s) || t.s[t.pos] == ',' {
+		return tok, true, nil
+	}
+
+	t.pos++ // '='
+	tok.end = t.pos
+	t.skip(isOWS)
+	tok.hasValue = true
+	if t.pos < len(t.s) && t.s[t.pos] == '"' {
+		start = t.pos
+		value, ok := t.quotedString()
+		tok.rawValue = t.s[start:t.pos]
+		tok.end = t.pos
+		if !ok {
+			return tok, true, errors.New("syntax error: unterminated quoted-string")
+		}
+		tok.value = value
+		t.skip(isOWS)
+		if t.pos < len(t.s) && t.s[t.pos] != ',' {
+			t.skip(func(c byte) bool { return c != ',' })
+			tok.end = start + len(strings.TrimRight(t.s[start:t.pos], " \t"))
+			return tok, true, errors.New("syntax error: unexpected characters after quoted-string")
+		}
+		return tok, true, nil
+	}
+	start = t.pos
+	t.skip(func(c byte) bool { return c != ',' })
+	tok.rawValue = strings.TrimRight(t.s[start:t.pos], " \t")
+	tok.value = tok.rawValue
+	if tok.rawValue != "" {
+		tok.end = start + len(tok.rawValue)
+	}
+	return tok, true, nil
+}
+
+// quotedString reads a quoted-string starting at the current position and returns it unquoted.
+// A quoted-pair, i.e. a backslash followed by a character, stands for the character.
+// It returns false if the quoted-string is not terminated.
+func (t *tokenizer) quotedString() (string, bool) {
+	var b strings.Builder
+	t.pos++ // opening '"'
+	for t.pos < len(t.s) {
+		c := t.s[t.pos]
+		t.pos++
+		switch c {
+		case '"':
+			return b.String(), true
+		case '\\':
+			if t.pos >= len(t.s) {
+				return b.String(), false
+			}
+			b.WriteByte(t.s[t.pos])
+			t.pos++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), false
+}
+
+// unquote returns s unquoted if it is a quoted-string, or s as is otherwise.
+// An unterminated quoted-string is unquoted up to its end.
+func unquote(s string) string {
+	if s == "" || s[0] != '"' {
+		return s
+	}
+	t := tokenizer{s: s}
+	v, _ := t.quotedString()
+	return v
+}
+
+// skip advances the position while f returns true.
+func (t *tokenizer) skip(f func(byte) bool) {
+	for t.pos < len(t.s) && f(t.s[t.pos]) {
+		t.pos++
+	}
+}
+
+func isOWS(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// isToken reports whether s is a token (RFC 9110 Section 5.6.2), i.e. one or more tchars.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// quoteString returns s as a quoted-string, escaping backslashes and double quotes.
+func quoteString(s string) string {
+	var b strings.Builder
+	b.Grow(len(s) + 2)
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// splitFieldNames splits a comma-separated list of field names, e.g. the value of `private="Set-Cookie, Authorization"`.
+// Empty elements are skipped, and the order is preserved.
+func splitFieldNames(s string) []string {
+	var fs []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fs = append(fs, f)
+		}
+	}
+	return fs
+}
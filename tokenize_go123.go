@@ -0,0 +1,33 @@
+//go:build go1.23
+// +build go1.23
+
+package cachecontrolheader
+
+import "iter"
+
+// Token is a directive of a Cache-Control header as written, returned by [Tokenize].
+type Token struct {
+	Name     string // name as written, not lowercased
+	Value    string // value, with a quoted-string unquoted
+	HasValue bool   // whether the directive has a value, i.e. `name=value` rather than `name`
+}
+
+// Tokenize returns an iterator over the directives of a Cache-Control header,
+// without checking whether they are known or their values are valid.
+// It is meant for callers implementing their own handling of directives.
+// A directive with a syntax error, e.g. an unterminated quoted-string,
+// is yielded with the value read so far.
+func Tokenize(header string) iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		t := tokenizer{s: header}
+		for {
+			tok, ok, _ := t.next()
+			if !ok {
+				return
+			}
+			if !yield(Token{Name: tok.name, Value: tok.value, HasValue: tok.hasValue}) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,43 @@
+//go:build go1.23
+// +build go1.23
+
+package cachecontrolheader_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mi-wada/cachecontrolheader"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Parallel()
+	var got []cachecontrolheader.Token
+	// Call the iterator directly rather than ranging over it, since the module's go version predates range-over-func.
+	cachecontrolheader.Tokenize(`Max-Age=60, no-store, x-unknown=1, private="Set-Cookie, X-A", ,max-stale=bogus`)(func(tok cachecontrolheader.Token) bool {
+		got = append(got, tok)
+		return true
+	})
+	want := []cachecontrolheader.Token{
+		{Name: "Max-Age", Value: "60", HasValue: true},
+		{Name: "no-store"},
+		{Name: "x-unknown", Value: "1", HasValue: true},
+		{Name: "private", Value: "Set-Cookie, X-A", HasValue: true},
+		{Name: "max-stale", Value: "bogus", HasValue: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Tokenize mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTokenize_break(t *testing.T) {
+	t.Parallel()
+	var got []string
+	cachecontrolheader.Tokenize("public, max-age=60, private")(func(tok cachecontrolheader.Token) bool {
+		got = append(got, tok.Name)
+		return len(got) < 2
+	})
+	if diff := cmp.Diff([]string{"public", "max-age"}, got); diff != "" {
+		t.Errorf("Tokenize mismatch (-want +got):\n%s", diff)
+	}
+}